@@ -0,0 +1,91 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ImageData holds straight (non-premultiplied) alpha, which corresponds to color.NRGBA.
+// The helpers below convert explicitly between that representation and the
+// premultiplied color.RGBA used by most of the image and image/draw packages.
+
+// Premultiply converts a straight alpha color to premultiplied alpha.
+func Premultiply(c color.NRGBA) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+// Unpremultiply converts a premultiplied alpha color to straight alpha.
+func Unpremultiply(c color.RGBA) color.NRGBA {
+	return color.NRGBAModel.Convert(c).(color.NRGBA)
+}
+
+// PremultiplyBytes converts RGBA bytes, as returned by ImageData.Bytes, to premultiplied alpha in place.
+func PremultiplyBytes(b []byte) {
+	for o := 0; o+3 < len(b); o += 4 {
+		a := uint32(b[o+3])
+		if a == 0xff {
+			continue
+		}
+		b[o] = uint8((uint32(b[o])*a + 0x7f) / 0xff)
+		b[o+1] = uint8((uint32(b[o+1])*a + 0x7f) / 0xff)
+		b[o+2] = uint8((uint32(b[o+2])*a + 0x7f) / 0xff)
+	}
+}
+
+// UnpremultiplyBytes converts premultiplied RGBA bytes back to straight alpha in place,
+// e.g. before passing them to ImageData.SetBytes.
+func UnpremultiplyBytes(b []byte) {
+	for o := 0; o+3 < len(b); o += 4 {
+		a := uint32(b[o+3])
+		switch a {
+		case 0xff:
+			continue
+		case 0:
+			b[o], b[o+1], b[o+2] = 0, 0, 0
+			continue
+		}
+		b[o] = clampByte((uint32(b[o])*0xff + a/2) / a)
+		b[o+1] = clampByte((uint32(b[o+1])*0xff + a/2) / a)
+		b[o+2] = clampByte((uint32(b[o+2])*0xff + a/2) / a)
+	}
+}
+
+func clampByte(v uint32) uint8 {
+	if v > 0xff {
+		return 0xff
+	}
+	return uint8(v)
+}
+
+// AtRGBA returns the pixel at (x, y) with premultiplied alpha.
+func (i *ImageData) AtRGBA(x, y int) color.RGBA {
+	return Premultiply(*i.At(x, y))
+}
+
+// SetRGBA sets the pixel at (x, y) from a premultiplied alpha color.
+func (i *ImageData) SetRGBA(x, y int, c color.RGBA) {
+	i.Set(x, y, Unpremultiply(c))
+}
+
+// SetColor sets the pixel at (x, y) from any color, converting it to straight alpha.
+func (i *ImageData) SetColor(x, y int, c color.Color) {
+	i.Set(x, y, color.NRGBAModel.Convert(c).(color.NRGBA))
+}
+
+// Image returns a copy of the pixels as an *image.NRGBA, read with a single bulk copy.
+func (i *ImageData) Image() *image.NRGBA {
+	return &image.NRGBA{
+		Pix:    i.Bytes(),
+		Stride: 4 * i.Width,
+		Rect:   image.Rect(0, 0, i.Width, i.Height),
+	}
+}
+
+// SetImage replaces the pixels with those of img, aligning its top-left corner with
+// the top-left corner of the ImageData. Premultiplied images are converted to straight alpha.
+func (i *ImageData) SetImage(img image.Image) {
+	dst := image.NewNRGBA(image.Rect(0, 0, i.Width, i.Height))
+	draw.Draw(dst, dst.Rect, img, img.Bounds().Min, draw.Src)
+	i.SetBytes(dst.Pix)
+}
@@ -0,0 +1,152 @@
+package canvas
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Assets preloads the images, sprite sheets and fonts an application needs, reports
+// progress for a loading screen and hands out the loaded assets by name.
+//
+//	a := canvas.NewAssets()
+//	a.AddImage("bg", "img/background.png")
+//	a.AddSpriteSheet("hero", "img/hero.png", 32, 32)
+//	a.AddFont("Pixel", "fonts/pixel.woff2")
+//	done := a.Load(func(loaded, total int) { drawProgress(loaded, total) })
+//	if err := <-done; err != nil { ... }
+type Assets struct {
+	// Cache loads the images; DefaultImageCache unless replaced before Load.
+	Cache *ImageCache
+
+	images map[string]string
+	sheets map[string]*sheetAsset
+	fonts  map[string]string
+	loaded map[string]JSImage
+}
+
+type sheetAsset struct {
+	url                     string
+	frameWidth, frameHeight float64
+}
+
+// NewAssets creates an empty asset list.
+func NewAssets() *Assets {
+	return &Assets{
+		Cache:  DefaultImageCache,
+		images: map[string]string{},
+		sheets: map[string]*sheetAsset{},
+		fonts:  map[string]string{},
+		loaded: map[string]JSImage{},
+	}
+}
+
+// AddImage registers the image at url under name.
+func (a *Assets) AddImage(name, url string) {
+	a.images[name] = url
+}
+
+// AddSpriteSheet registers the image at url under name, to be cut into frames of the given size.
+func (a *Assets) AddSpriteSheet(name, url string, frameWidth, frameHeight float64) {
+	a.sheets[name] = &sheetAsset{url, frameWidth, frameHeight}
+}
+
+// AddFont registers the font file at url as the font family name. Once loaded it is
+// added to document.fonts and can be used in Context2D.Font.
+func (a *Assets) AddFont(family, url string) {
+	a.fonts[family] = url
+}
+
+// Load starts loading every registered asset. onProgress, if not nil, is called with
+// the number of finished and total assets after each one finishes, whether it loaded
+// or failed. The returned channel receives nil once all assets have loaded, or an
+// error listing the messages of the failed ones.
+func (a *Assets) Load(onProgress func(loaded, total int)) <-chan error {
+	done := make(chan error, 1)
+	total := len(a.images) + len(a.sheets) + len(a.fonts)
+	if total == 0 {
+		done <- nil
+		return done
+	}
+	var errs []string
+	finished := 0
+	finish := func(err error) {
+		finished++
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		if onProgress != nil {
+			onProgress(finished, total)
+		}
+		if finished == total {
+			if len(errs) > 0 {
+				// errors.Join needs Go 1.20, newer than the standard library of GopherJS
+				done <- errors.New(strings.Join(errs, "; "))
+			} else {
+				done <- nil
+			}
+		}
+	}
+	loadImage := func(url string) {
+		a.Cache.Load(url, func(img JSImage, err error) {
+			if err == nil {
+				a.loaded[url] = img
+			}
+			finish(err)
+		})
+	}
+	for _, url := range a.images {
+		loadImage(url)
+	}
+	for _, s := range a.sheets {
+		loadImage(s.url)
+	}
+	for family, url := range a.fonts {
+		loadFont(family, url, finish)
+	}
+	return done
+}
+
+func loadFont(family, url string, done func(error)) {
+	ctor := js.Global.Get("FontFace")
+	if ctor == js.Undefined {
+		done(errors.New("canvas: FontFace is not supported, cannot load " + family))
+		return
+	}
+	face := ctor.New(family, "url("+url+")")
+	face.Call("load").Call("then", func(f *js.Object) {
+		js.Global.Get("document").Get("fonts").Call("add", f)
+		done(nil)
+	}, func(e *js.Object) {
+		done(errors.New("canvas: loading font " + family + " failed: " + e.String()))
+	})
+}
+
+// Image returns the loaded image registered under name.
+func (a *Assets) Image(name string) (JSImage, bool) {
+	img, ok := a.loaded[a.images[name]]
+	return img, ok
+}
+
+// SpriteSheet returns the loaded sprite sheet registered under name.
+func (a *Assets) SpriteSheet(name string) (*SpriteSheet, bool) {
+	s := a.sheets[name]
+	if s == nil {
+		return nil, false
+	}
+	img, ok := a.loaded[s.url]
+	if !ok {
+		return nil, false
+	}
+	return NewSpriteSheet(img, s.frameWidth, s.frameHeight), true
+}
+
+// FontLoaded reports whether the font family registered with AddFont is ready to use.
+func (a *Assets) FontLoaded(family string) bool {
+	fonts := js.Global.Get("document").Get("fonts")
+	if _, ok := a.fonts[family]; !ok || fonts == js.Undefined {
+		return false
+	}
+	return fonts.Call("check", "1em \""+family+"\"").Bool()
+}
@@ -0,0 +1,175 @@
+package canvas
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ErrAudioUnsupported is returned when the browser has no Web Audio API.
+var ErrAudioUnsupported = errors.New("canvas: Web Audio not supported")
+
+// Analyser reads waveform and spectrum data from a Web Audio AnalyserNode into
+// reused buffers and draws them as visualizations.
+type Analyser struct {
+	// Node is the AnalyserNode.
+	Node *js.Object
+
+	wave, freq       *js.Object
+	waveBuf, freqBuf []byte
+}
+
+// NewAnalyser wraps an existing AnalyserNode.
+func NewAnalyser(node *js.Object) *Analyser {
+	a := &Analyser{Node: node}
+	a.alloc()
+	return a
+}
+
+// AnalyseElement connects an <audio> or <video> element to a new AudioContext through
+// an AnalyserNode with the given FFT size, a power of two between 32 and 32768.
+// The element keeps playing through the speakers. Browsers only start an AudioContext
+// after a user gesture, so call this from a click handler or resume the context later
+// through Node.get("context").
+func AnalyseElement(media *js.Object, fftSize int) (*Analyser, error) {
+	ctor := js.Global.Get("AudioContext")
+	if ctor == js.Undefined {
+		ctor = js.Global.Get("webkitAudioContext")
+	}
+	if ctor == js.Undefined {
+		return nil, ErrAudioUnsupported
+	}
+	actx := ctor.New()
+	node := actx.Call("createAnalyser")
+	node.Set("fftSize", fftSize)
+	src := actx.Call("createMediaElementSource", media)
+	src.Call("connect", node)
+	node.Call("connect", actx.Get("destination"))
+	return NewAnalyser(node), nil
+}
+
+func (a *Analyser) alloc() {
+	u8 := js.Global.Get("Uint8Array")
+	a.wave = u8.New(a.Node.Get("fftSize"))
+	a.freq = u8.New(a.Node.Get("frequencyBinCount"))
+	a.waveBuf = a.wave.Interface().([]byte)
+	a.freqBuf = a.freq.Interface().([]byte)
+}
+
+// SetFFTSize changes the FFT size of the node and reallocates the buffers.
+func (a *Analyser) SetFFTSize(n int) {
+	a.Node.Set("fftSize", n)
+	a.alloc()
+}
+
+// Waveform returns the current time domain samples, 128 meaning silence.
+// The slice is reused by the next call.
+func (a *Analyser) Waveform() []byte {
+	a.Node.Call("getByteTimeDomainData", a.wave)
+	return a.waveBuf
+}
+
+// Frequencies returns the current magnitude of each frequency bin, 0 to 255, from
+// low to high frequencies. The slice is reused by the next call.
+func (a *Analyser) Frequencies() []byte {
+	a.Node.Call("getByteFrequencyData", a.freq)
+	return a.freqBuf
+}
+
+// WaveformStyle configures DrawWaveform.
+type WaveformStyle struct {
+	// Stroke is the line style; black if nil.
+	Stroke Style
+	// LineWidth defaults to 2.
+	LineWidth float64
+	// Background, if not nil, fills the area first.
+	Background Style
+}
+
+// DrawWaveform reads the current samples and draws them as a line across r,
+// silence running through its vertical center.
+func (a *Analyser) DrawWaveform(ctx *Context2D, r Rect, style WaveformStyle) {
+	data := a.Waveform()
+	ctx.Save()
+	if style.Background != nil {
+		ctx.FillStyle = jsStyle(style.Background)
+		ctx.FillRect(r.X, r.Y, r.W, r.H)
+	}
+	ctx.StrokeStyle = "black"
+	if style.Stroke != nil {
+		ctx.StrokeStyle = jsStyle(style.Stroke)
+	}
+	ctx.LineWidth = 2
+	if style.LineWidth > 0 {
+		ctx.LineWidth = style.LineWidth
+	}
+	ctx.BeginPath()
+	step := r.W / float64(len(data)-1)
+	for i, v := range data {
+		x, y := r.X+float64(i)*step, r.Y+r.H-float64(v)/255*r.H
+		if i == 0 {
+			ctx.MoveTo(x, y)
+		} else {
+			ctx.LineTo(x, y)
+		}
+	}
+	ctx.Stroke()
+	ctx.Restore()
+}
+
+// BarsStyle configures DrawBars.
+type BarsStyle struct {
+	// Bars is the number of bars; the frequency bins are averaged into them.
+	// 0 draws one bar per bin.
+	Bars int
+	// Gap is the space between bars in pixels.
+	Gap float64
+	// Fill is the bar style; black if nil. A vertical gradient spanning r colors
+	// bars by height.
+	Fill Style
+	// Background, if not nil, fills the area first.
+	Background Style
+	// MaxFrequency, if positive, drops the bins above this frequency in Hz,
+	// which for music are mostly empty.
+	MaxFrequency float64
+}
+
+// DrawBars reads the current spectrum and draws it as bars growing up from the
+// bottom of r.
+func (a *Analyser) DrawBars(ctx *Context2D, r Rect, style BarsStyle) {
+	data := a.Frequencies()
+	if style.MaxFrequency > 0 {
+		nyquist := a.Node.Get("context").Get("sampleRate").Float() / 2
+		if n := int(style.MaxFrequency / nyquist * float64(len(data))); n > 0 && n < len(data) {
+			data = data[:n]
+		}
+	}
+	bars := style.Bars
+	if bars <= 0 || bars > len(data) {
+		bars = len(data)
+	}
+	ctx.Save()
+	if style.Background != nil {
+		ctx.FillStyle = jsStyle(style.Background)
+		ctx.FillRect(r.X, r.Y, r.W, r.H)
+	}
+	ctx.FillStyle = "black"
+	if style.Fill != nil {
+		ctx.FillStyle = jsStyle(style.Fill)
+	}
+	w := r.W / float64(bars)
+	bw := w - style.Gap
+	if bw < 1 {
+		bw = 1
+	}
+	for b := 0; b < bars; b++ {
+		lo, hi := b*len(data)/bars, (b+1)*len(data)/bars
+		sum := 0
+		for _, v := range data[lo:hi] {
+			sum += int(v)
+		}
+		h := float64(sum) / float64(hi-lo) / 255 * r.H
+		ctx.FillRect(r.X+float64(b)*w, r.Y+r.H-h, bw, h)
+	}
+	ctx.Restore()
+}
@@ -0,0 +1,176 @@
+package canvas
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Brush turns a stream of input points into stamps of a brush tip, the core of a paint tool.
+// The zero value is not usable; create brushes with NewBrush.
+type Brush struct {
+	// Size is the diameter of the tip at full pressure.
+	Size float64
+	// Hardness between 0 and 1 sets where a round tip starts fading out; 1 gives a hard edge.
+	Hardness float64
+	// Opacity of each stamp between 0 and 1.
+	Opacity float64
+	// Spacing between stamps as a fraction of the current tip size.
+	Spacing float64
+	// Color of a round tip.
+	Color color.Color
+	// Texture, if set, is stamped instead of a round tip.
+	Texture CanvasImageSource
+	// PressureSize and PressureOpacity make pointer pressure scale the tip size and opacity.
+	PressureSize, PressureOpacity bool
+	// CompositeOp used while stamping, e.g. CompositeDestinationOut for an eraser;
+	// source-over if empty.
+	CompositeOp CompositeOp
+
+	ctx      *Context2D
+	stamp    *Canvas
+	stampKey [2]float64
+	stampCol color.NRGBA
+	last     Point
+	lastP    float64
+	carry    float64
+	active   bool
+}
+
+// NewBrush creates a round, fairly hard, opaque brush painting on ctx.
+func NewBrush(ctx *Context2D, size float64, c color.Color) *Brush {
+	return &Brush{
+		Size:         size,
+		Hardness:     0.8,
+		Opacity:      1,
+		Spacing:      0.15,
+		Color:        c,
+		PressureSize: true,
+		ctx:          ctx,
+	}
+}
+
+// Begin starts a stroke at p, stamping the tip once.
+// pressure is between 0 and 1; pass 1 for devices without pressure.
+func (b *Brush) Begin(p Point, pressure float64) {
+	b.active = true
+	b.last, b.lastP = p, pressure
+	b.carry = 0
+	b.stampAt(p, pressure)
+}
+
+// MoveTo continues the stroke to p, stamping the tip at regular spacing along the way.
+func (b *Brush) MoveTo(p Point, pressure float64) {
+	if !b.active {
+		b.Begin(p, pressure)
+		return
+	}
+	d := math.Hypot(p.X-b.last.X, p.Y-b.last.Y)
+	t := 0.0
+	for {
+		pr := b.lastP + (pressure-b.lastP)*t
+		step := math.Max(b.Spacing*b.sizeAt(pr), 0.5)
+		if d-t*d < step-b.carry {
+			break
+		}
+		t += (step - b.carry) / math.Max(d, 1e-9)
+		b.carry = 0
+		b.stampAt(b.last.Add(p.Sub(b.last).Mul(t)), b.lastP+(pressure-b.lastP)*t)
+	}
+	b.carry += d - t*d
+	b.last, b.lastP = p, pressure
+}
+
+// End finishes the stroke.
+func (b *Brush) End() {
+	b.active = false
+}
+
+// Attach drives the brush from the pointer events of c, using the pressure reported by the device.
+// The returned function detaches it.
+func (b *Brush) Attach(c *Canvas) (detach func()) {
+	pressure := func(ev *js.Object) float64 {
+		p := ev.Get("pressure")
+		if p == js.Undefined || ev.Get("pointerType").String() == "mouse" {
+			return 1
+		}
+		return p.Float()
+	}
+	return c.onPointerDrag(
+		func(p Point, ev *js.Object) {
+			c.Call("setPointerCapture", ev.Get("pointerId"))
+			b.Begin(p, pressure(ev))
+		},
+		func(p Point, ev *js.Object) {
+			if b.active {
+				b.MoveTo(p, pressure(ev))
+			}
+		},
+		func(p Point, ev *js.Object) { b.End() },
+	)
+}
+
+func (b *Brush) sizeAt(pressure float64) float64 {
+	if b.PressureSize {
+		return math.Max(b.Size*pressure, 1)
+	}
+	return b.Size
+}
+
+func (b *Brush) stampAt(p Point, pressure float64) {
+	size := b.sizeAt(pressure)
+	alpha := b.Opacity
+	if b.PressureOpacity {
+		alpha *= pressure
+	}
+	var tip CanvasImageSource = b.Texture
+	if tip == nil {
+		tip = b.roundTip()
+	}
+	ctx := b.ctx
+	ctx.Save()
+	ctx.GlobalAlpha = alpha
+	if b.CompositeOp != "" {
+		ctx.GlobalCompositeOperation = b.CompositeOp
+	}
+	ctx.DrawImage(tip, p.X-size/2, p.Y-size/2, size, size)
+	ctx.Restore()
+}
+
+// roundTip returns the cached round stamp, redrawing it when the brush settings changed.
+func (b *Brush) roundTip() *Canvas {
+	col := color.NRGBAModel.Convert(b.Color).(color.NRGBA)
+	key := [2]float64{math.Ceil(b.Size), b.Hardness}
+	if b.stamp != nil && key == b.stampKey && col == b.stampCol {
+		return b.stamp
+	}
+	n := int(key[0])
+	if n < 1 {
+		n = 1
+	}
+	if b.stamp == nil {
+		b.stamp = Create(n, n)
+	} else {
+		b.stamp.Set("width", n)
+		b.stamp.Set("height", n)
+	}
+	ctx := b.stamp.GetContext2D()
+	r := float64(n) / 2
+	hard := math.Max(0, math.Min(1, b.Hardness))
+	opaque := col
+	opaque.A = 0xff
+	transparent := opaque
+	transparent.A = 0
+	g := ctx.CreateRadialGradient(r, r, 0, r, r, r).Stops([]Stop{
+		{0, opaque},
+		{hard * 0.999, opaque},
+		{1, transparent},
+	})
+	ctx.FillStyle = g.Value()
+	ctx.BeginPath()
+	ctx.Arc(r, r, r, 0, 2*math.Pi)
+	ctx.Fill()
+	b.stampKey, b.stampCol = key, col
+	return b.stamp
+}
@@ -0,0 +1,176 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// The batch methods below hand coordinates to JavaScript as the typed array backing
+// the Go slice, together with the offset and length of the slice, so no array or
+// view is created per call. The JavaScript side only reads the data during the call
+// and keeps no reference to it: a slice passed to a batch method may be modified or
+// reused as soon as the method returns. Coords helps to build such slices once and
+// reuse them from frame to frame.
+//
+// The JavaScript functions are compiled on first use.
+var (
+	tracePolylineJS   *js.Object
+	fillRectsJS       *js.Object
+	fillRectsColorsJS *js.Object
+)
+
+// typedView returns the typed array backing s and the offset and length of s in it.
+// GopherJS stores []float64 in a Float64Array.
+func typedView(s []float64) (arr *js.Object, off, n int) {
+	if len(s) == 0 {
+		return js.Global.Get("Float64Array").New(0), 0, 0
+	}
+	o := js.InternalObject(s)
+	return o.Get("$array"), o.Get("$offset").Int(), len(s)
+}
+
+func bulkPath(ctx *Context2D, xy []float64, close bool) {
+	if tracePolylineJS == nil {
+		tracePolylineJS = js.Global.Get("Function").New("ctx", "a", "off", "n", "close", `
+			if (n < 4) return;
+			var end = off + n - 1;
+			ctx.moveTo(a[off], a[off + 1]);
+			for (var i = off + 2; i < end; i += 2) ctx.lineTo(a[i], a[i + 1]);
+			if (close) ctx.closePath();
+		`)
+	}
+	arr, off, n := typedView(xy)
+	tracePolylineJS.Invoke(ctx.Object, arr, off, n, close)
+}
+
+// PolylineFast adds the polyline with the flat coordinates xy (x0, y0, x1, y1, ...)
+// to the current path in a single call into JavaScript, instead of one call per
+// vertex as with MoveTo and LineTo. Use it for very large point sets.
+func (ctx *Context2D) PolylineFast(xy []float64) {
+	bulkPath(ctx, xy, false)
+}
+
+// StrokePolylineFast strokes the open polyline with the flat coordinates xy
+// (x0, y0, x1, y1, ...), building the path in a single call into JavaScript.
+func (ctx *Context2D) StrokePolylineFast(xy []float64) {
+	ctx.BeginPath()
+	bulkPath(ctx, xy, false)
+	ctx.Stroke()
+}
+
+// FillPolygonFast fills the polygon with the flat coordinates xy (x0, y0, x1, y1, ...),
+// building the path in a single call into JavaScript.
+func (ctx *Context2D) FillPolygonFast(xy []float64) {
+	ctx.BeginPath()
+	bulkPath(ctx, xy, true)
+	ctx.Fill()
+}
+
+// StrokePolygonFast strokes the closed polygon with the flat coordinates xy
+// (x0, y0, x1, y1, ...), building the path in a single call into JavaScript.
+func (ctx *Context2D) StrokePolygonFast(xy []float64) {
+	ctx.BeginPath()
+	bulkPath(ctx, xy, true)
+	ctx.Stroke()
+}
+
+// FillRects fills all rects with the current fill style as one path, crossing into
+// JavaScript once for the whole batch.
+func (ctx *Context2D) FillRects(rects []Rect) {
+	ctx.FillRectsFlat(flattenRects(rects))
+}
+
+// FillRectsFlat is FillRects for rectangles given as flat x, y, w, h values,
+// e.g. built with Coords.AddRect.
+func (ctx *Context2D) FillRectsFlat(xywh []float64) {
+	if fillRectsJS == nil {
+		fillRectsJS = js.Global.Get("Function").New("ctx", "a", "off", "n", `
+			var end = off + n - 3;
+			ctx.beginPath();
+			for (var i = off; i < end; i += 4) ctx.rect(a[i], a[i + 1], a[i + 2], a[i + 3]);
+			ctx.fill();
+		`)
+	}
+	arr, off, n := typedView(xywh)
+	fillRectsJS.Invoke(ctx.Object, arr, off, n)
+}
+
+// FillRectsWithColors fills each rect with the style of the same index, crossing into
+// JavaScript once for the whole batch. The fill style is only changed when it differs
+// from the previous rect's. The fill style of ctx is restored afterwards.
+// It panics if rects and colors differ in length.
+func (ctx *Context2D) FillRectsWithColors(rects []Rect, colors []Style) {
+	if len(colors) != len(rects) {
+		panic("canvas: FillRectsWithColors needs one color per rect")
+	}
+	if fillRectsColorsJS == nil {
+		fillRectsColorsJS = js.Global.Get("Function").New("ctx", "a", "off", "n", "c", `
+			var saved = ctx.fillStyle, last, end = off + n - 3;
+			for (var i = off, j = 0; i < end; i += 4, j++) {
+				if (c[j] !== last) { ctx.fillStyle = c[j]; last = c[j]; }
+				ctx.fillRect(a[i], a[i + 1], a[i + 2], a[i + 3]);
+			}
+			ctx.fillStyle = saved;
+		`)
+	}
+	styles := make([]interface{}, len(rects))
+	for i := range styles {
+		styles[i] = jsStyle(colors[i])
+	}
+	arr, off, n := typedView(flattenRects(rects))
+	fillRectsColorsJS.Invoke(ctx.Object, arr, off, n, styles)
+}
+
+// rectScratch is reused by flattenRects; the batch methods never hold on to it.
+var rectScratch Coords
+
+func flattenRects(rects []Rect) []float64 {
+	rectScratch.Reset()
+	for _, r := range rects {
+		rectScratch.AddRect(r)
+	}
+	return rectScratch.Values()
+}
+
+// Coords is a growable buffer of flat coordinates for the batch drawing methods.
+// Reset and refill the same Coords every frame to avoid allocating: its storage is
+// kept between uses and, like any slice passed to a batch method, handed to
+// JavaScript without copying.
+type Coords struct {
+	buf []float64
+}
+
+// NewCoords returns a buffer with room for n values.
+func NewCoords(n int) *Coords {
+	return &Coords{buf: make([]float64, 0, n)}
+}
+
+// Reset empties the buffer, keeping its storage.
+func (c *Coords) Reset() {
+	c.buf = c.buf[:0]
+}
+
+// Add appends a point.
+func (c *Coords) Add(x, y float64) {
+	c.buf = append(c.buf, x, y)
+}
+
+// AddPoints appends points.
+func (c *Coords) AddPoints(pts []Point) {
+	for _, p := range pts {
+		c.buf = append(c.buf, p.X, p.Y)
+	}
+}
+
+// AddRect appends a rectangle as x, y, w, h, for FillRectsFlat.
+func (c *Coords) AddRect(r Rect) {
+	c.buf = append(c.buf, r.X, r.Y, r.W, r.H)
+}
+
+// Len returns the number of values in the buffer.
+func (c *Coords) Len() int {
+	return len(c.buf)
+}
+
+// Values returns the contents of the buffer. The slice shares storage with the
+// buffer and is only valid until the next change to it.
+func (c *Coords) Values() []float64 {
+	return c.buf
+}
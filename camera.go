@@ -0,0 +1,151 @@
+package canvas
+
+import "math"
+
+// Camera is a 2D view onto a world: it maps world coordinates to screen coordinates
+// with a pan, zoom and rotation. The world point (X, Y) appears at the centre of the
+// viewport, scaled by Zoom and rotated by Rotation radians around it.
+type Camera struct {
+	// X and Y are the world point shown at the centre of the viewport.
+	X, Y float64
+	// Zoom is the number of screen units per world unit.
+	Zoom float64
+	// Rotation of the view in radians, clockwise.
+	Rotation float64
+	// ViewWidth and ViewHeight are the size of the viewport in screen units,
+	// usually the CSS size of the canvas.
+	ViewWidth, ViewHeight float64
+	// MinZoom and MaxZoom limit Zoom in ZoomAt and Fit; 0 means no limit.
+	MinZoom, MaxZoom float64
+	// YUp flips the y axis so that world y grows upwards, as in mathematical plots.
+	// Use the camera's FillText and StrokeText to keep text readable.
+	YUp bool
+}
+
+// NewCamera creates a camera for a viewport of the given size showing the world
+// origin in the centre at zoom 1.
+func NewCamera(viewWidth, viewHeight float64) *Camera {
+	return &Camera{Zoom: 1, ViewWidth: viewWidth, ViewHeight: viewHeight}
+}
+
+// Matrix returns the world-to-screen transform.
+func (cam *Camera) Matrix() Matrix {
+	sin, cos := math.Sincos(cam.Rotation)
+	a, b := cos*cam.Zoom, sin*cam.Zoom
+	c, d := -sin*cam.Zoom*cam.ySign(), cos*cam.Zoom*cam.ySign()
+	return Matrix{a, b, c, d,
+		cam.ViewWidth/2 - a*cam.X - c*cam.Y,
+		cam.ViewHeight/2 - b*cam.X - d*cam.Y,
+	}
+}
+
+// Apply multiplies the transform of ctx by the camera transform, so that world
+// coordinates can be used for drawing. Because it multiplies rather than replaces
+// the transform, the HiDPI scaling of the context is preserved; call it on a
+// context whose transform only maps screen units.
+func (cam *Camera) Apply(ctx *Context2D) {
+	ctx.TransformBy(cam.Matrix())
+}
+
+// WorldToScreen converts a world point to screen coordinates.
+func (cam *Camera) WorldToScreen(p Point) Point {
+	return cam.Matrix().TransformPoint(p)
+}
+
+// ScreenToWorld converts a point in screen coordinates, such as the result of
+// Canvas.EventPoint, to world coordinates.
+func (cam *Camera) ScreenToWorld(p Point) Point {
+	sin, cos := math.Sincos(cam.Rotation)
+	x, y := p.X-cam.ViewWidth/2, p.Y-cam.ViewHeight/2
+	return Point{
+		X: cam.X + (x*cos+y*sin)/cam.Zoom,
+		Y: cam.Y + (-x*sin+y*cos)/cam.Zoom*cam.ySign(),
+	}
+}
+
+// Pan moves the view by dx, dy screen units, as when dragging the world with the pointer.
+func (cam *Camera) Pan(dx, dy float64) {
+	sin, cos := math.Sincos(cam.Rotation)
+	cam.X -= (dx*cos + dy*sin) / cam.Zoom
+	cam.Y -= (-dx*sin + dy*cos) / cam.Zoom * cam.ySign()
+}
+
+// ZoomAt multiplies the zoom by factor, keeping the world point under the screen
+// point p in place, as when zooming with the mouse wheel.
+func (cam *Camera) ZoomAt(p Point, factor float64) {
+	before := cam.ScreenToWorld(p)
+	cam.Zoom = cam.clampZoom(cam.Zoom * factor)
+	after := cam.ScreenToWorld(p)
+	cam.X += before.X - after.X
+	cam.Y += before.Y - after.Y
+}
+
+// Fit centres r in the viewport and zooms so that it fits with padding screen units
+// to spare on every side, taking the rotation into account.
+func (cam *Camera) Fit(r Rect, padding float64) {
+	cam.X, cam.Y = r.X+r.W/2, r.Y+r.H/2
+	sin, cos := math.Sincos(cam.Rotation)
+	// size of the rotated rectangle's bounding box at zoom 1
+	w := math.Abs(r.W*cos) + math.Abs(r.H*sin)
+	h := math.Abs(r.W*sin) + math.Abs(r.H*cos)
+	vw, vh := cam.ViewWidth-2*padding, cam.ViewHeight-2*padding
+	if w <= 0 || h <= 0 || vw <= 0 || vh <= 0 {
+		return
+	}
+	cam.Zoom = cam.clampZoom(math.Min(vw/w, vh/h))
+}
+
+// VisibleBounds returns the axis-aligned world rectangle covering the viewport,
+// useful to skip drawing what is off screen.
+func (cam *Camera) VisibleBounds() Rect {
+	return BoundingBox([]Point{
+		cam.ScreenToWorld(Point{}),
+		cam.ScreenToWorld(Point{X: cam.ViewWidth}),
+		cam.ScreenToWorld(Point{X: cam.ViewWidth, Y: cam.ViewHeight}),
+		cam.ScreenToWorld(Point{Y: cam.ViewHeight}),
+	})
+}
+
+func (cam *Camera) clampZoom(z float64) float64 {
+	if cam.MinZoom > 0 && z < cam.MinZoom {
+		z = cam.MinZoom
+	}
+	if cam.MaxZoom > 0 && z > cam.MaxZoom {
+		z = cam.MaxZoom
+	}
+	return z
+}
+
+func (cam *Camera) ySign() float64 {
+	if cam.YUp {
+		return -1
+	}
+	return 1
+}
+
+// FillText draws text with its baseline at the world point x, y. With YUp the text
+// is flipped back so it reads upright instead of mirrored.
+func (cam *Camera) FillText(ctx *Context2D, text string, x, y float64) {
+	if !cam.YUp {
+		ctx.FillText(text, x, y)
+		return
+	}
+	ctx.Save()
+	ctx.Translate(x, y)
+	ctx.Scale(1, -1)
+	ctx.FillText(text, 0, 0)
+	ctx.Restore()
+}
+
+// StrokeText is the stroking counterpart of FillText.
+func (cam *Camera) StrokeText(ctx *Context2D, text string, x, y float64) {
+	if !cam.YUp {
+		ctx.StrokeText(text, x, y)
+		return
+	}
+	ctx.Save()
+	ctx.Translate(x, y)
+	ctx.Scale(1, -1)
+	ctx.StrokeText(text, 0, 0)
+	ctx.Restore()
+}
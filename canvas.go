@@ -5,55 +5,147 @@
 package canvas
 
 import (
+	"fmt"
 	"image/color"
+	"strings"
 
 	"github.com/gopherjs/gopherjs/js"
 	"github.com/oskca/gopherjs-dom"
 )
 
+// CompositeOp is a value of Context2D.GlobalCompositeOperation.
+// Any other operation supported by the browser can be used by conversion, e.g. CompositeOp("plus-lighter").
+type CompositeOp string
+
 // The CanvasRenderingContext2D.globalCompositeOperation property of the Canvas 2D API sets the type of
 // compositing operation to apply when drawing new shapes, where type is a string identifying which of
 // the compositing or blending mode operations to use.
 const (
 	// This is the default setting and draws new shapes on top of the existing canvas content.
-	CompositeSourceOver = "source-over"
+	CompositeSourceOver CompositeOp = "source-over"
 	// New shapes are drawn behind the existing canvas content.
-	CompositeDestinationOver = "destination-over"
+	CompositeDestinationOver CompositeOp = "destination-over"
 	// The new shape is drawn only where both the new shape and the destination canvas overlap.
 	// Everything else is made transparent.
-	CompositeSourceIn = "source-in"
+	CompositeSourceIn CompositeOp = "source-in"
 	// The existing canvas content is kept where both the new shape and existing canvas content overlap.
 	// Everything else is made transparent.
-	CompositeDestinationIn = "destination-in"
+	CompositeDestinationIn CompositeOp = "destination-in"
 	// The new shape is drawn where it doesn't overlap the existing canvas content.
-	CompositeSourceOut = "source-out"
+	CompositeSourceOut CompositeOp = "source-out"
 	// The existing content is kept where it doesn't overlap the new shape.
-	CompositeDestinationOut = "destination-out"
+	CompositeDestinationOut CompositeOp = "destination-out"
 	// The new shape is only drawn where it overlaps the existing canvas content.
-	CompositeSourceAtop = "source-atop"
+	CompositeSourceAtop CompositeOp = "source-atop"
 	// The existing canvas is only kept where it overlaps the new shape.
 	// The new shape is drawn behind the canvas content.
-	CompositeDestinationAtop = "destination-atop"
+	CompositeDestinationAtop CompositeOp = "destination-atop"
 	// Where both shapes overlap the color is determined by adding color values.
-	CompositeLighter = "lighter"
+	CompositeLighter CompositeOp = "lighter"
 	// Shapes are made transparent where both overlap and drawn normal everywhere else.
-	CompositeXor = "xor"
+	CompositeXor CompositeOp = "xor"
 	// Only the new shape is shown.
-	CompositeCopy = "copy"
+	CompositeCopy CompositeOp = "copy"
+	// The pixels of the top layer are multiplied with the corresponding pixel of the bottom layer.
+	// A darker picture is the result.
+	CompositeMultiply CompositeOp = "multiply"
+	// The pixels are inverted, multiplied, and inverted again. A lighter picture is the result (opposite of multiply).
+	CompositeScreen CompositeOp = "screen"
+	// A combination of multiply and screen. Dark parts on the base layer become darker, and light parts become lighter.
+	CompositeOverlay CompositeOp = "overlay"
+	// Retains the darkest pixels of both layers.
+	CompositeDarken CompositeOp = "darken"
+	// Retains the lightest pixels of both layers.
+	CompositeLighten CompositeOp = "lighten"
+	// Divides the bottom layer by the inverted top layer.
+	CompositeColorDodge CompositeOp = "color-dodge"
+	// Divides the inverted bottom layer by the top layer, and then inverts the result.
+	CompositeColorBurn CompositeOp = "color-burn"
+	// A combination of multiply and screen like overlay, but with top and bottom layer swapped.
+	CompositeHardLight CompositeOp = "hard-light"
+	// A softer version of hard-light. Pure black or white does not result in pure black or white.
+	CompositeSoftLight CompositeOp = "soft-light"
+	// Subtracts the bottom layer from the top layer or the other way round to always get a positive value.
+	CompositeDifference CompositeOp = "difference"
+	// Like difference, but with lower contrast.
+	CompositeExclusion CompositeOp = "exclusion"
+	// Preserves the luma and chroma of the bottom layer, while adopting the hue of the top layer.
+	CompositeHue CompositeOp = "hue"
+	// Preserves the luma and hue of the bottom layer, while adopting the chroma of the top layer.
+	CompositeSaturation CompositeOp = "saturation"
+	// Preserves the luma of the bottom layer, while adopting the hue and chroma of the top layer.
+	CompositeColor CompositeOp = "color"
+	// Preserves the hue and chroma of the bottom layer, while adopting the luma of the top layer.
+	CompositeLuminosity CompositeOp = "luminosity"
 )
 
+// Repetition is the repetition argument of CreatePattern.
+type Repetition string
+
 // Repeat Patterns
 const (
-	PatternRepeat   = "repeat"    // (both directions),
-	PatternRepeatX  = "repeat-x"  // (horizontal only),
-	PatternRepeatY  = "repeat-y"  // (vertical only), or
-	PatternNoRepeat = "no-repeat" // (neither).
+	PatternRepeat   Repetition = "repeat"    // (both directions),
+	PatternRepeatX  Repetition = "repeat-x"  // (horizontal only),
+	PatternRepeatY  Repetition = "repeat-y"  // (vertical only), or
+	PatternNoRepeat Repetition = "no-repeat" // (neither).
+)
+
+// LineCap is a value of Context2D.LineCap.
+type LineCap string
+
+// Line caps, the values of Context2D.LineCap
+const (
+	LineCapButt   LineCap = "butt"   // The ends of lines are squared off at the endpoints. (default)
+	LineCapRound  LineCap = "round"  // The ends of lines are rounded.
+	LineCapSquare LineCap = "square" // The ends of lines are squared off by adding a box of half the line width.
+)
+
+// LineJoin is a value of Context2D.LineJoin.
+type LineJoin string
+
+// Line joins, the values of Context2D.LineJoin
+const (
+	LineJoinRound LineJoin = "round" // Rounds off the corners of a shape.
+	LineJoinBevel LineJoin = "bevel" // Fills an additional triangular area between the common endpoint of connected segments.
+	LineJoinMiter LineJoin = "miter" // Connected segments are joined by extending their outside edges to connect at a single point. (default)
+)
+
+// TextAlign is a value of Context2D.TextAlign.
+type TextAlign string
+
+// Text alignments, the values of Context2D.TextAlign
+const (
+	TextAlignLeft   TextAlign = "left"   // The text is left-aligned.
+	TextAlignRight  TextAlign = "right"  // The text is right-aligned.
+	TextAlignCenter TextAlign = "center" // The text is centered.
+	TextAlignStart  TextAlign = "start"  // The text is aligned at the normal start of the line. (default)
+	TextAlignEnd    TextAlign = "end"    // The text is aligned at the normal end of the line.
+)
+
+// TextBaseline is a value of Context2D.TextBaseline.
+type TextBaseline string
+
+// Text baselines, the values of Context2D.TextBaseline
+const (
+	TextBaselineTop         TextBaseline = "top"         // The top of the em square.
+	TextBaselineHanging     TextBaseline = "hanging"     // The hanging baseline, used by Tibetan and other Indic scripts.
+	TextBaselineMiddle      TextBaseline = "middle"      // The middle of the em square.
+	TextBaselineAlphabetic  TextBaseline = "alphabetic"  // The normal alphabetic baseline. (default)
+	TextBaselineIdeographic TextBaseline = "ideographic" // The ideographic baseline, the bottom of the body of the characters.
+	TextBaselineBottom      TextBaseline = "bottom"      // The bottom of the bounding box.
 )
 
 // Canvas The HTML5 <canvas> tag is used to draw graphics, on the fly,
 // via scripting (usually JavaScript).
 type Canvas struct {
 	*dom.Element
+
+	pixelRatio float64
+	ctxAttrs   js.M
+	ctx        *Context2D
+	onResize   func(c *Canvas)
+	observer   *js.Object
+	preserve   *ScaleMode
 }
 
 // Context2D struct
@@ -82,13 +174,15 @@ type Context2D struct {
 	ShadowOffsetY float64 `js:"shadowOffsetY"`
 
 	// Type of endings on the end of lines. Possible values: butt (default), round, square.
-	LineCap string `js:"lineCap"`
+	LineCap LineCap `js:"lineCap"`
 	// Defines the type of corners where two lines meet. Possible values: round, bevel, miter (default).
-	LineJoin string `js:"lineJoin"`
+	LineJoin LineJoin `js:"lineJoin"`
 	// Width of lines. Default 1.0
 	LineWidth float64 `js:"lineWidth"`
 	// Miter limit ratio. Default 10.
 	MiterLimit float64 `js:"miterLimit"`
+	// Specifies where to start a dash array on a line. Used to animate dashed lines. Default 0.
+	LineDashOffset float64 `js:"lineDashOffset"`
 
 	// A string parsed as CSS font value. The default font is '10px sans-serif'.
 	// Syntax:
@@ -102,9 +196,9 @@ type Context2D struct {
 	//	    font: message-box;
 	Font string `js:"font"`
 	// ctx.textAlign = "left" || "right" || "center" || "start" || "end";
-	TextAlign string `js:"textAlign"`
+	TextAlign TextAlign `js:"textAlign"`
 	// ctx.textBaseline = "top" || "hanging" || "middle" || "alphabetic" || "ideographic" || "bottom";
-	TextBaseline string `js:"textBaseline"`
+	TextBaseline TextBaseline `js:"textBaseline"`
 
 	// Compositing
 	// specifies the alpha value that is applied to shapes and images before they are drawn onto the canvas.
@@ -112,30 +206,129 @@ type Context2D struct {
 	GlobalAlpha float64 `js:"globalAlpha"`
 	// the type of compositing operation to apply when drawing new shapes,
 	// where type is a string identifying which of the compositing or blending mode operations to use.
-	GlobalCompositeOperation string `js:"globalCompositeOperation"`
+	GlobalCompositeOperation CompositeOp `js:"globalCompositeOperation"`
+
+	// xf mirrors the transform stack in Go once TrackTransform is enabled.
+	xf *transformStack
 }
 
 // New creates a Canvas instance
-// el is the html element
-func New(el *js.Object) *Canvas {
-	return &Canvas{dom.WrapElement(el)}
+// el is the html element, opts configure its size, pixel ratio and context.
+func New(el *js.Object, opts ...Option) *Canvas {
+	c := &Canvas{Element: dom.WrapElement(el), pixelRatio: 1}
+	c.apply(opts)
+	return c
+}
+
+// FromSelector returns the first element matching the CSS selector sel,
+// failing if there is none or if it is not a <canvas>.
+func FromSelector(sel string, opts ...Option) (*Canvas, error) {
+	el := js.Global.Get("document").Call("querySelector", sel)
+	if el == nil {
+		return nil, fmt.Errorf("canvas: no element matches %q", sel)
+	}
+	return fromElement(el, sel, opts)
 }
 
-// GetContext2D returns the Context2D object
-func (c *Canvas) GetContext2D() *Context2D {
-	ctx := c.Call("getContext", "2d")
-	return &Context2D{Object: ctx}
+// FromID returns the element with the given id, failing if there is none or if it is not a <canvas>.
+func FromID(id string, opts ...Option) (*Canvas, error) {
+	el := js.Global.Get("document").Call("getElementById", id)
+	if el == nil {
+		return nil, fmt.Errorf("canvas: no element with id %q", id)
+	}
+	return fromElement(el, "#"+id, opts)
+}
+
+func fromElement(el *js.Object, desc string, opts []Option) (*Canvas, error) {
+	if tag := el.Get("tagName").String(); !strings.EqualFold(tag, "canvas") {
+		return nil, fmt.Errorf("canvas: %s is a <%s>, not a <canvas>", desc, strings.ToLower(tag))
+	}
+	return New(el, opts...), nil
+}
+
+// WrapCanvas wraps an existing HTMLCanvasElement, e.g. one obtained from another JavaScript library.
+func WrapCanvas(o *js.Object) *Canvas {
+	return New(o)
+}
+
+// WrapContext2D wraps an existing CanvasRenderingContext2D object so it can be used
+// with the typed API of this package.
+func WrapContext2D(o *js.Object) *Context2D {
+	return &Context2D{Object: o}
+}
+
+// Canvas returns the canvas element the context belongs to.
+func (ctx *Context2D) Canvas() *Canvas {
+	return New(ctx.Get("canvas"))
+}
+
+// Create creates a new <canvas> element of the given size that is not attached to the document,
+// e.g. for use as an offscreen scratch surface. opts are applied after sizing,
+// so WithSize overrides width and height.
+func Create(width, height int, opts ...Option) *Canvas {
+	c := New(js.Global.Get("document").Call("createElement", "canvas"))
+	c.Set("width", width)
+	c.Set("height", height)
+	c.apply(opts)
+	return c
+}
+
+// Clone returns a new, detached canvas with the same backing store size, pixel ratio
+// and CSS size as c and a copy of its current contents. The context of the clone is
+// scaled by the pixel ratio like that of c, but other context state isn't copied.
+func (c *Canvas) Clone() *Canvas {
+	w, h := c.Size()
+	dst := Create(w, h)
+	dst.pixelRatio = c.pixelRatio
+	if c.PixelRatio() != 1 {
+		src, style := c.Get("style"), dst.Get("style")
+		style.Set("width", src.Get("width"))
+		style.Set("height", src.Get("height"))
+	}
+	ctx := dst.GetContext2D()
+	ctx.Save()
+	ctx.SetTransform(1, 0, 0, 1, 0, 0)
+	ctx.DrawImage(c, 0, 0, float64(w), float64(h))
+	ctx.Restore()
+	return dst
 }
 
-// toDataURL canvas.toDataURL("image/jpeg") or canvas.toDataURL()
-func (c *Canvas) toDataURL(mimeType ...string) string {
-	var o *js.Object
-	if len(mimeType) == 0 {
-		o = c.Call("toDataURL")
+// GetContext2D returns the Context2D object
+// Only WithContextAttributes is meaningful in opts, and only on the first call:
+// the browser hands out the same context afterwards.
+func (c *Canvas) GetContext2D(opts ...Option) *Context2D {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	attrs := c.ctxAttrs
+	if o.attrs != nil {
+		attrs = o.attrs
+	}
+	var ctx *js.Object
+	if attrs != nil {
+		ctx = c.Call("getContext", "2d", attrs)
 	} else {
-		o = c.Call("toDataURL", mimeType)
+		ctx = c.Call("getContext", "2d")
 	}
-	return o.String()
+	c.ctx = &Context2D{Object: ctx}
+	c.applyPixelRatio()
+	return c.ctx
+}
+
+// ToDataURL The HTMLCanvasElement.toDataURL() method returns a data URI containing a representation
+// of the image in the format specified by mimeType, such as "image/jpeg" or "image/webp".
+// An empty mimeType gives "image/png". quality between 0 and 1 applies to lossy formats;
+// if omitted or not positive the browser default is used.
+func (c *Canvas) ToDataURL(mimeType string, quality ...float64) string {
+	q := 0.0
+	if len(quality) > 0 {
+		q = quality[0]
+	}
+	return dataURL(c, mimeType, q)
 }
 
 // Gradient Colors, Styles, and Shadows
@@ -214,6 +407,13 @@ func (p *Pattern) Value() *js.Object {
 	return p.o
 }
 
+// SetTransform sets the transformation matrix of the pattern, applied on top of the
+// transform of the context it is used with. The arguments have the same meaning as in
+// Context2D.SetTransform.
+func (p *Pattern) SetTransform(a, b, c, d, e, f float64) {
+	p.o.Call("setTransform", js.M{"a": a, "b": b, "c": c, "d": d, "e": e, "f": f})
+}
+
 // CreatePattern The CanvasRenderingContext2D.createPattern() method of the Canvas 2D API creates a
 // pattern using the specified image (a CanvasImageSource).
 // It repeats the source in the directions specified by the repetition argument. This method returns a
@@ -249,8 +449,8 @@ func (p *Pattern) Value() *js.Object {
 // 			  ctx.fillStyle = pattern;
 // 			  ctx.fillRect(0,0,400,400);
 // 			};
-func (ctx *Context2D) CreatePattern(image *dom.Element, repetition string) *Pattern {
-	o := ctx.Call("createPattern", image.Object, repetition)
+func (ctx *Context2D) CreatePattern(image CanvasImageSource, repetition Repetition) *Pattern {
+	o := ctx.Call("createPattern", image.ImageSource(), string(repetition))
 	return &Pattern{o: o}
 }
 
@@ -269,7 +469,11 @@ func (ctx *Context2D) SetLineDash(distances ...float64) {
 // GetLineDash Returns the current line dash pattern array containing an even number of non-negative numbers.
 func (ctx *Context2D) GetLineDash() []float64 {
 	o := ctx.Call("getLineDash")
-	return o.Interface().([]float64)
+	dash := make([]float64, o.Length())
+	for i := range dash {
+		dash[i] = o.Index(i).Float()
+	}
+	return dash
 }
 
 // Rect The CanvasRenderingContext2D.rect() method of the Canvas 2D API creates a path for
@@ -356,8 +560,19 @@ func (ctx *Context2D) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) {
 // Arc Adds an arc to the path which is centered at (x, y) position with
 // radius r starting at startAngle and ending at endAngle going in the given direction by
 // anticlockwise (defaulting to clockwise).
-func (ctx *Context2D) Arc(x, y, radius, sAngle, eAngle float64, counterclockwise bool) {
-	ctx.Call("arc", x, y, radius, sAngle, eAngle, counterclockwise)
+// counterclockwise is optional; only its first value is used.
+func (ctx *Context2D) Arc(x, y, radius, sAngle, eAngle float64, counterclockwise ...bool) {
+	ctx.Call("arc", x, y, radius, sAngle, eAngle, len(counterclockwise) > 0 && counterclockwise[0])
+}
+
+// ArcCW Adds a clockwise arc to the path, see Arc.
+func (ctx *Context2D) ArcCW(x, y, radius, sAngle, eAngle float64) {
+	ctx.Call("arc", x, y, radius, sAngle, eAngle, false)
+}
+
+// ArcCCW Adds a counterclockwise arc to the path, see Arc.
+func (ctx *Context2D) ArcCCW(x, y, radius, sAngle, eAngle float64) {
+	ctx.Call("arc", x, y, radius, sAngle, eAngle, true)
 }
 
 // ArcTo Adds an arc to the path with the given control points and radius,
@@ -386,6 +601,9 @@ func (ctx *Context2D) IsPointInStroke(x, y float64) bool {
 // This results in shapes being drawn twice as large.
 func (ctx *Context2D) Scale(scaleWidth, scaleHeight float64) {
 	ctx.Call("scale", scaleWidth, scaleHeight)
+	if ctx.xf != nil {
+		ctx.xf.apply(Scaling(scaleWidth, scaleHeight))
+	}
 }
 
 // Rotate The CanvasRenderingContext2D.rotate() method of the Canvas 2D API adds a
@@ -394,12 +612,18 @@ func (ctx *Context2D) Scale(scaleWidth, scaleHeight float64) {
 // You can use degree * Math.PI / 180 if you want to calculate from a degree value.
 func (ctx *Context2D) Rotate(angle float64) {
 	ctx.Call("rotate", angle)
+	if ctx.xf != nil {
+		ctx.xf.apply(Rotation(angle))
+	}
 }
 
 // Translate The CanvasRenderingContext2D.translate() method of the Canvas 2D API
 // adds a translation transformation by moving the canvas and its origin x horizontally and y vertically on the grid.
 func (ctx *Context2D) Translate(x, y float64) {
 	ctx.Call("translate", x, y)
+	if ctx.xf != nil {
+		ctx.xf.apply(Translation(x, y))
+	}
 }
 
 // Transform The CanvasRenderingContext2D.transform() method of the Canvas 2D API
@@ -419,6 +643,9 @@ func (ctx *Context2D) Translate(x, y float64) {
 //    	Vertical moving.
 func (ctx *Context2D) Transform(a, b, c, d, e, f float64) {
 	ctx.Call("transform", a, b, c, d, e, f)
+	if ctx.xf != nil {
+		ctx.xf.apply(Matrix{a, b, c, d, e, f})
+	}
 }
 
 // SetTransform The CanvasRenderingContext2D.setTransform() method of the Canvas 2D API
@@ -438,26 +665,58 @@ func (ctx *Context2D) Transform(a, b, c, d, e, f float64) {
 //    	Vertical moving.
 func (ctx *Context2D) SetTransform(a, b, c, d, e, f float64) {
 	ctx.Call("setTransform", a, b, c, d, e, f)
+	if ctx.xf != nil {
+		ctx.xf.set(Matrix{a, b, c, d, e, f})
+	}
 }
 
 // FillText Draws (fills) a given text at the given (x,y) position.
-func (ctx *Context2D) FillText(text string, x, y, maxWidth float64) {
-	if maxWidth == -1 {
+// If maxWidth is given the text is scaled horizontally to fit into it.
+// For compatibility a negative maxWidth, such as the former -1 sentinel, means no limit.
+func (ctx *Context2D) FillText(text string, x, y float64, maxWidth ...float64) {
+	if len(maxWidth) == 0 || maxWidth[0] < 0 {
 		ctx.Call("fillText", text, x, y)
 		return
 	}
 
-	ctx.Call("fillText", text, x, y, maxWidth)
+	ctx.Call("fillText", text, x, y, maxWidth[0])
 }
 
 // StrokeText Draws (strokes) a given text at the given (x, y) position.
-func (ctx *Context2D) StrokeText(text string, x, y, maxWidth float64) {
-	if maxWidth == -1 {
+// If maxWidth is given the text is scaled horizontally to fit into it.
+// For compatibility a negative maxWidth, such as the former -1 sentinel, means no limit.
+func (ctx *Context2D) StrokeText(text string, x, y float64, maxWidth ...float64) {
+	if len(maxWidth) == 0 || maxWidth[0] < 0 {
 		ctx.Call("strokeText", text, x, y)
 		return
 	}
 
-	ctx.Call("strokeText", text, x, y, maxWidth)
+	ctx.Call("strokeText", text, x, y, maxWidth[0])
+}
+
+// SetCompositeOp Sets the type of compositing operation to apply when drawing new shapes.
+func (ctx *Context2D) SetCompositeOp(op CompositeOp) {
+	ctx.GlobalCompositeOperation = op
+}
+
+// SetLineCap Sets the shape used to draw the end points of lines.
+func (ctx *Context2D) SetLineCap(c LineCap) {
+	ctx.LineCap = c
+}
+
+// SetLineJoin Sets the shape used to join two line segments where they meet.
+func (ctx *Context2D) SetLineJoin(j LineJoin) {
+	ctx.LineJoin = j
+}
+
+// SetTextAlign Sets the text alignment used when drawing text.
+func (ctx *Context2D) SetTextAlign(a TextAlign) {
+	ctx.TextAlign = a
+}
+
+// SetTextBaseline Sets the text baseline used when drawing text.
+func (ctx *Context2D) SetTextBaseline(b TextBaseline) {
+	ctx.TextBaseline = b
 }
 
 // canvas state
@@ -466,17 +725,29 @@ func (ctx *Context2D) StrokeText(text string, x, y, maxWidth float64) {
 // a stack so you can revert any change you make to it using restore()
 func (ctx *Context2D) Save() {
 	ctx.Call("save")
+	if ctx.xf != nil {
+		ctx.xf.save()
+	}
 }
 
 // Restore Restores the drawing style state to the last element on the 'state stack' saved by save().
 func (ctx *Context2D) Restore() {
 	ctx.Call("restore")
+	if ctx.xf != nil {
+		ctx.xf.restore()
+	}
 }
 
 // DrawImage Draws the specified image. This method is available in multiple formats,
 // providing a great deal of flexibility in its use.
-func (ctx *Context2D) DrawImage(image *dom.Element, dx, dy, dw, dh float64) {
-	ctx.Call("drawImage", image, dx, dy, dw, dh)
+func (ctx *Context2D) DrawImage(image CanvasImageSource, dx, dy, dw, dh float64) {
+	ctx.Call("drawImage", image.ImageSource(), dx, dy, dw, dh)
+}
+
+// DrawImageRegion Draws the sub-rectangle (sx, sy, sw, sh) of the specified image into
+// the rectangle (dx, dy, dw, dh) of the canvas, scaling it as needed.
+func (ctx *Context2D) DrawImageRegion(image CanvasImageSource, sx, sy, sw, sh, dx, dy, dw, dh float64) {
+	ctx.Call("drawImage", image.ImageSource(), sx, sy, sw, sh, dx, dy, dw, dh)
 }
 
 // ImageData struct
@@ -498,6 +769,11 @@ func (i *ImageData) Bytes() []byte {
 	return js.Global.Get("Uint8Array").New(i.Data).Interface().([]byte)
 }
 
+// SetBytes copies b, as returned by Bytes, back into the ImageData in a single call.
+func (i *ImageData) SetBytes(b []byte) {
+	i.Data.Call("set", b)
+}
+
 // At ImageData At
 func (i *ImageData) At(x, y int) *color.NRGBA {
 	idx := 4 * (y*i.Width + x)
@@ -506,7 +782,6 @@ func (i *ImageData) At(x, y int) *color.NRGBA {
 	rgba.G = uint8(i.Data.Index(idx + 1).Int())
 	rgba.B = uint8(i.Data.Index(idx + 2).Int())
 	rgba.A = uint8(i.Data.Index(idx + 3).Int())
-	println("at:", x, y, rgba)
 	return rgba
 }
 
@@ -519,14 +794,41 @@ func (i *ImageData) Set(x, y int, c color.NRGBA) {
 	i.Data.SetIndex(idx+3, c.A)
 }
 
-// func (i *ImageData) Image() image.Image {
-// 	data := js.Global.Get("Uint8Array").New(i.Data).Interface().([]uint8)
-// 	rgba := new(image.RGBA)
-// 	rgba.Pix = data
-// 	rgba.Stride = i.Width * 4
-// 	rgba.Rect = image.Rect(0, 0, i.Width, i.Height)
-// 	return rgba
-// }
+// Pixels returns all pixels in row order, read with a single bulk copy.
+// ImageData stores straight (non-premultiplied) alpha, so like At the pixels are color.NRGBA.
+func (i *ImageData) Pixels() []color.NRGBA {
+	b := i.Bytes()
+	px := make([]color.NRGBA, len(b)/4)
+	for j := range px {
+		o := 4 * j
+		px[j] = color.NRGBA{b[o], b[o+1], b[o+2], b[o+3]}
+	}
+	return px
+}
+
+// SetPixels writes px, as returned by Pixels, back with a single bulk copy.
+func (i *ImageData) SetPixels(px []color.NRGBA) {
+	b := make([]byte, 4*len(px))
+	for j, c := range px {
+		o := 4 * j
+		b[o], b[o+1], b[o+2], b[o+3] = c.R, c.G, c.B, c.A
+	}
+	i.SetBytes(b)
+}
+
+// ForEachPixel calls fn for every pixel and stores the color it returns.
+// The pixels are read and written back with one bulk copy each instead of per-pixel calls.
+func (i *ImageData) ForEachPixel(fn func(x, y int, c color.NRGBA) color.NRGBA) {
+	b := i.Bytes()
+	for y := 0; y < i.Height; y++ {
+		for x := 0; x < i.Width; x++ {
+			o := 4 * (y*i.Width + x)
+			c := fn(x, y, color.NRGBA{b[o], b[o+1], b[o+2], b[o+3]})
+			b[o], b[o+1], b[o+2], b[o+3] = c.R, c.G, c.B, c.A
+		}
+	}
+	i.SetBytes(b)
+}
 
 // CreateImageData The CanvasRenderingContext2D.createImageData() method of the Canvas 2D API creates a new, blank ImageData object with the specified dimensions.
 // All of the pixels in the new object are transparent black.
@@ -561,29 +863,68 @@ func (ctx *Context2D) GetImageData(x, y, width, heigth int) *ImageData {
 }
 
 // PutImageData The CanvasRenderingContext2D.putImageData() method of the Canvas 2D API paints data from
-// the given ImageData object onto the bitmap. If a dirty rectangle is provided,
-// only the pixels from that rectangle are painted.
+// the given ImageData object onto the bitmap. Use PutImageDataDirty to paint only part of it.
 // Syntax
 // void ctx.putImageData(imagedata, dx, dy);
-// void ctx.putImageData(imagedata, dx, dy, dirtyX, dirtyY, dirtyWidth, dirtyHeight);
 // 	imageData
 // 		An ImageData object containing the array of pixel values.
 // 	dx
 // 		Position offset in the target canvas context of the rectangle to be painted, relative to the rectangle in the origin image data.
 // 	dy
 // 		Position offset in the target canvas context of the rectangle to be painted, relative to the rectangle in the origin image data.
-// 	dirtyX Optional
-// 		Position of the top left point of the rectangle to be painted, in the origin image data. Defaults to the top left of the whole image data.
-// 	dirtyY Optional
-// 		Position of the top left point of the rectangle to be painted, in the origin image data. Defaults to the top left of the whole image data.
-// 	dirtyWidth Optional
-// 		Width of the rectangle to be painted, in the origin image data. Defaults to the width of the image data.
-// 	dirtyHeight Optional
-// 		Height of the rectangle to be painted, in the origin image data. Defaults to the height of the image data.
-func (ctx *Context2D) PutImageData(imd *ImageData, x, y int, dirtyX ...int) {
-	args := []interface{}{imd.Object, x, y}
-	for _, v := range dirtyX {
-		args = append(args, v)
-	}
-	ctx.Call("putImageData", args...)
+func (ctx *Context2D) PutImageData(imd *ImageData, dx, dy int) {
+	ctx.Call("putImageData", imd.Object, dx, dy)
+}
+
+// PutImageDataDirty Paints only the dirty rectangle of the given ImageData object onto the bitmap.
+// Syntax
+// void ctx.putImageData(imagedata, dx, dy, dirtyX, dirtyY, dirtyWidth, dirtyHeight);
+// 	dirtyX
+// 		Position of the top left point of the rectangle to be painted, in the origin image data.
+// 	dirtyY
+// 		Position of the top left point of the rectangle to be painted, in the origin image data.
+// 	dirtyWidth
+// 		Width of the rectangle to be painted, in the origin image data.
+// 	dirtyHeight
+// 		Height of the rectangle to be painted, in the origin image data.
+func (ctx *Context2D) PutImageDataDirty(imd *ImageData, dx, dy, dirtyX, dirtyY, dirtyWidth, dirtyHeight int) {
+	ctx.Call("putImageData", imd.Object, dx, dy, dirtyX, dirtyY, dirtyWidth, dirtyHeight)
+}
+
+// Clear clears every pixel of the context's canvas, ignoring the current transform,
+// which ClearRect(0, 0, width, height) doesn't under a zoom or pan.
+// The clip region still applies.
+func (ctx *Context2D) Clear() {
+	c := ctx.Get("canvas")
+	ctx.Save()
+	ctx.SetTransform(1, 0, 0, 1, 0, 0)
+	ctx.ClearRect(0, 0, c.Get("width").Float(), c.Get("height").Float())
+	ctx.Restore()
+}
+
+// ClearWithColor clears the whole canvas like Clear and fills it with style, usually a
+// CSS color string or a color.Color. Translucent colors replace the previous content
+// instead of being blended over it. The clip region still applies.
+func (ctx *Context2D) ClearWithColor(style Style) {
+	c := ctx.Get("canvas")
+	w, h := c.Get("width").Float(), c.Get("height").Float()
+	ctx.Save()
+	ctx.SetTransform(1, 0, 0, 1, 0, 0)
+	ctx.ClearRect(0, 0, w, h)
+	ctx.GlobalAlpha = 1
+	ctx.GlobalCompositeOperation = CompositeSourceOver
+	ctx.FillStyle = jsStyle(style)
+	ctx.FillRect(0, 0, w, h)
+	ctx.Restore()
+}
+
+// Clear clears the whole canvas, ignoring the transform of its context.
+func (c *Canvas) Clear() {
+	c.GetContext2D().Clear()
+}
+
+// ClearWithColor clears the whole canvas and fills it with style, ignoring the
+// transform of its context.
+func (c *Canvas) ClearWithColor(style Style) {
+	c.GetContext2D().ClearWithColor(style)
 }
@@ -0,0 +1,114 @@
+// Package canvastest provides helpers for visual regression tests of canvas drawing code
+// running in the browser.
+package canvastest
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sort"
+
+	canvas "github.com/oskca/gopherjs-canvas"
+)
+
+// RegionSize is the edge length in pixels of the tiles mismatches are grouped into.
+const RegionSize = 16
+
+// Result describes the differences between a canvas and a golden image.
+type Result struct {
+	// Mismatched is the number of pixels differing by more than the tolerance.
+	Mismatched int
+	// Total is the number of compared pixels.
+	Total int
+	// Bounds is the smallest rectangle containing all mismatched pixels.
+	Bounds image.Rectangle
+	// Regions lists the RegionSize tiles containing mismatched pixels, in row order.
+	Regions []image.Rectangle
+	// Diff is an image of the canvas with mismatched pixels painted red.
+	Diff *image.NRGBA
+}
+
+// Ok reports whether no mismatching pixels were found.
+func (r *Result) Ok() bool {
+	return r.Mismatched == 0
+}
+
+// Err returns nil for a matching result and a descriptive error otherwise.
+func (r *Result) Err() error {
+	if r.Ok() {
+		return nil
+	}
+	return fmt.Errorf("canvastest: %d of %d pixels differ within %v (%d regions)",
+		r.Mismatched, r.Total, r.Bounds, len(r.Regions))
+}
+
+// DiffPNG encodes Diff as PNG, e.g. to attach it to a failing test report.
+func (r *Result) DiffPNG() ([]byte, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, r.Diff)
+	return buf.Bytes(), err
+}
+
+// Compare diffs the pixels of c against the PNG encoded goldenPNG.
+// tolerance is the allowed per-channel difference as a fraction between 0 and 1;
+// 0 requires an exact match. An error is returned if the golden image can not be decoded
+// or its size differs from the canvas.
+func Compare(c *canvas.Canvas, goldenPNG []byte, tolerance float64) (*Result, error) {
+	golden, err := png.Decode(bytes.NewReader(goldenPNG))
+	if err != nil {
+		return nil, fmt.Errorf("canvastest: decoding golden image: %v", err)
+	}
+	w, h := c.Get("width").Int(), c.Get("height").Int()
+	gb := golden.Bounds()
+	if gb.Dx() != w || gb.Dy() != h {
+		return nil, fmt.Errorf("canvastest: canvas is %dx%d, golden image is %dx%d", w, h, gb.Dx(), gb.Dy())
+	}
+	pix := c.GetContext2D().GetImageData(0, 0, w, h).Bytes()
+	actual := &image.NRGBA{Pix: pix, Stride: 4 * w, Rect: image.Rect(0, 0, w, h)}
+	return CompareImages(actual, golden, tolerance), nil
+}
+
+// CompareImages diffs two images of the same size; see Compare.
+func CompareImages(actual, golden image.Image, tolerance float64) *Result {
+	b := actual.Bounds()
+	gb := golden.Bounds()
+	limit := int(tolerance*0xff + 0.5)
+	res := &Result{Total: b.Dx() * b.Dy(), Diff: image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))}
+	draw.Draw(res.Diff, res.Diff.Rect, actual, b.Min, draw.Src)
+	tiles := map[image.Point]bool{}
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			a := color.NRGBAModel.Convert(actual.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			g := color.NRGBAModel.Convert(golden.At(gb.Min.X+x, gb.Min.Y+y)).(color.NRGBA)
+			if diff(a.R, g.R) <= limit && diff(a.G, g.G) <= limit &&
+				diff(a.B, g.B) <= limit && diff(a.A, g.A) <= limit {
+				continue
+			}
+			res.Mismatched++
+			res.Diff.SetNRGBA(x, y, red)
+			res.Bounds = res.Bounds.Union(image.Rect(x, y, x+1, y+1))
+			tile := image.Pt(x/RegionSize, y/RegionSize)
+			if !tiles[tile] {
+				tiles[tile] = true
+				r := image.Rect(tile.X*RegionSize, tile.Y*RegionSize, (tile.X+1)*RegionSize, (tile.Y+1)*RegionSize)
+				res.Regions = append(res.Regions, r.Intersect(res.Diff.Rect))
+			}
+		}
+	}
+	sort.Slice(res.Regions, func(i, j int) bool {
+		a, b := res.Regions[i].Min, res.Regions[j].Min
+		return a.Y < b.Y || a.Y == b.Y && a.X < b.X
+	})
+	return res
+}
+
+func diff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
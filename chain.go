@@ -0,0 +1,159 @@
+package canvas
+
+// Chain is a fluent view of a Context2D whose methods return the chain itself,
+// so paths can be built in a single expression:
+//
+//	ctx.Begin().MoveTo(0, 0).LineTo(10, 10).StrokeWith("red")
+type Chain struct {
+	ctx *Context2D
+}
+
+// Begin starts a new path and returns a Chain operating on ctx.
+func (ctx *Context2D) Begin() *Chain {
+	ctx.BeginPath()
+	return &Chain{ctx}
+}
+
+// Chain returns a Chain operating on ctx without starting a new path.
+func (ctx *Context2D) Chain() *Chain {
+	return &Chain{ctx}
+}
+
+// Context returns the underlying Context2D.
+func (c *Chain) Context() *Context2D {
+	return c.ctx
+}
+
+// BeginPath starts a new path.
+func (c *Chain) BeginPath() *Chain {
+	c.ctx.BeginPath()
+	return c
+}
+
+// MoveTo moves the starting point of a new sub-path to (x, y).
+func (c *Chain) MoveTo(x, y float64) *Chain {
+	c.ctx.MoveTo(x, y)
+	return c
+}
+
+// LineTo connects the last point in the sub-path to (x, y) with a straight line.
+func (c *Chain) LineTo(x, y float64) *Chain {
+	c.ctx.LineTo(x, y)
+	return c
+}
+
+// QuadraticCurveTo adds a quadratic Bézier curve to the path.
+func (c *Chain) QuadraticCurveTo(cpx, cpy, x, y float64) *Chain {
+	c.ctx.QuadraticCurveTo(cpx, cpy, x, y)
+	return c
+}
+
+// BezierCurveTo adds a cubic Bézier curve to the path.
+func (c *Chain) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) *Chain {
+	c.ctx.BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y)
+	return c
+}
+
+// Arc adds an arc to the path.
+func (c *Chain) Arc(x, y, radius, sAngle, eAngle float64, counterclockwise ...bool) *Chain {
+	c.ctx.Arc(x, y, radius, sAngle, eAngle, counterclockwise...)
+	return c
+}
+
+// ArcTo adds an arc with the given control points and radius to the path.
+func (c *Chain) ArcTo(x1, y1, x2, y2, r float64) *Chain {
+	c.ctx.ArcTo(x1, y1, x2, y2, r)
+	return c
+}
+
+// Rect adds a rectangle to the path.
+func (c *Chain) Rect(x, y, width, height float64) *Chain {
+	c.ctx.Rect(x, y, width, height)
+	return c
+}
+
+// ClosePath closes the current sub-path.
+func (c *Chain) ClosePath() *Chain {
+	c.ctx.ClosePath()
+	return c
+}
+
+// Fill fills the path with the current fill style.
+func (c *Chain) Fill() *Chain {
+	c.ctx.Fill()
+	return c
+}
+
+// Stroke strokes the path with the current stroke style.
+func (c *Chain) Stroke() *Chain {
+	c.ctx.Stroke()
+	return c
+}
+
+// FillWith sets the fill style and fills the path.
+func (c *Chain) FillWith(style Style) *Chain {
+	c.ctx.FillStyle = jsStyle(style)
+	c.ctx.Fill()
+	return c
+}
+
+// StrokeWith sets the stroke style and strokes the path.
+func (c *Chain) StrokeWith(style Style) *Chain {
+	c.ctx.StrokeStyle = jsStyle(style)
+	c.ctx.Stroke()
+	return c
+}
+
+// LineWidth sets the line width.
+func (c *Chain) LineWidth(w float64) *Chain {
+	c.ctx.LineWidth = w
+	return c
+}
+
+// Clip turns the path into the clipping region.
+func (c *Chain) Clip() *Chain {
+	c.ctx.Clip()
+	return c
+}
+
+// Save pushes the drawing state.
+func (c *Chain) Save() *Chain {
+	c.ctx.Save()
+	return c
+}
+
+// Restore pops the drawing state.
+func (c *Chain) Restore() *Chain {
+	c.ctx.Restore()
+	return c
+}
+
+// Translate adds a translation to the transform.
+func (c *Chain) Translate(x, y float64) *Chain {
+	c.ctx.Translate(x, y)
+	return c
+}
+
+// Rotate adds a rotation to the transform.
+func (c *Chain) Rotate(angle float64) *Chain {
+	c.ctx.Rotate(angle)
+	return c
+}
+
+// Scale adds a scaling to the transform.
+func (c *Chain) Scale(x, y float64) *Chain {
+	c.ctx.Scale(x, y)
+	return c
+}
+
+// FillRect draws a filled rectangle.
+func (c *Chain) FillRect(x, y, width, height float64) *Chain {
+	c.ctx.FillRect(x, y, width, height)
+	return c
+}
+
+// StrokeRect draws a rectangle outline.
+func (c *Chain) StrokeRect(x, y, width, height float64) *Chain {
+	c.ctx.StrokeRect(x, y, width, height)
+	return c
+}
@@ -0,0 +1,115 @@
+package canvas
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ChunkOptions controls how RunChunked spreads work over time.
+type ChunkOptions struct {
+	// Budget is the time spent working before yielding to the browser, 8ms by default.
+	Budget time.Duration
+	// OnProgress, if set, is called after every slice with the completed fraction of the work.
+	OnProgress func(done float64)
+	// OnDone, if set, is called once when the work is finished or has been canceled.
+	OnDone func(canceled bool)
+}
+
+// Task is a running chunked operation.
+type Task struct {
+	canceled bool
+	finished bool
+	done     int
+	total    int
+}
+
+// Cancel stops the task before its next step. OnDone is called with canceled set.
+func (t *Task) Cancel() {
+	t.canceled = true
+}
+
+// Finished reports whether the task ran to completion.
+func (t *Task) Finished() bool {
+	return t.finished
+}
+
+// Progress returns the completed fraction of the task between 0 and 1.
+func (t *Task) Progress() float64 {
+	if t.total == 0 {
+		return 1
+	}
+	return float64(t.done) / float64(t.total)
+}
+
+// RunChunked calls step for every i in [0, n) without freezing the page: after each time
+// budget it yields with setTimeout and continues in a later task. opts may be nil.
+func RunChunked(n int, step func(i int), opts *ChunkOptions) *Task {
+	if opts == nil {
+		opts = &ChunkOptions{}
+	}
+	budget := float64(opts.Budget) / float64(time.Millisecond)
+	if budget <= 0 {
+		budget = 8
+	}
+	perf := js.Global.Get("performance")
+	t := &Task{total: n}
+	var slice func()
+	slice = func() {
+		if t.canceled {
+			if opts.OnDone != nil {
+				opts.OnDone(true)
+			}
+			return
+		}
+		deadline := perf.Call("now").Float() + budget
+		for t.done < n {
+			step(t.done)
+			t.done++
+			if perf.Call("now").Float() >= deadline {
+				break
+			}
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(t.Progress())
+		}
+		if t.done < n {
+			js.Global.Call("setTimeout", slice, 0)
+			return
+		}
+		t.finished = true
+		if opts.OnDone != nil {
+			opts.OnDone(false)
+		}
+	}
+	js.Global.Call("setTimeout", slice, 0)
+	return t
+}
+
+// ForEachPixelAsync is like ForEachPixel but processes the image row by row over several
+// browser tasks using RunChunked. The pixels are written back once all rows are done;
+// nothing is written if the task is canceled.
+func (i *ImageData) ForEachPixelAsync(fn func(x, y int, c color.NRGBA) color.NRGBA, opts *ChunkOptions) *Task {
+	b := i.Bytes()
+	var o ChunkOptions
+	if opts != nil {
+		o = *opts
+	}
+	userDone := o.OnDone
+	o.OnDone = func(canceled bool) {
+		if !canceled {
+			i.SetBytes(b)
+		}
+		if userDone != nil {
+			userDone(canceled)
+		}
+	}
+	return RunChunked(i.Height, func(y int) {
+		for x := 0; x < i.Width; x++ {
+			p := 4 * (y*i.Width + x)
+			c := fn(x, y, color.NRGBA{b[p], b[p+1], b[p+2], b[p+3]})
+			b[p], b[p+1], b[p+2], b[p+3] = c.R, c.G, c.B, c.A
+		}
+	}, &o)
+}
@@ -0,0 +1,93 @@
+package canvas
+
+import "math"
+
+// Clipper is implemented by shapes that can intersect the clip region of a context with themselves.
+type Clipper interface {
+	ClipOn(ctx *Context2D)
+}
+
+// RoundRect is a rectangle with corners rounded by radius R.
+type RoundRect struct {
+	X, Y, W, H, R float64
+}
+
+// ClipRect intersects the clip region with a rectangle.
+// Wrap it in Save/Restore, or use WithClip, to undo it.
+func (ctx *Context2D) ClipRect(x, y, width, height float64) {
+	ctx.BeginPath()
+	ctx.Rect(x, y, width, height)
+	ctx.Clip()
+}
+
+// ClipCircle intersects the clip region with a circle.
+func (ctx *Context2D) ClipCircle(x, y, r float64) {
+	ctx.BeginPath()
+	ctx.Arc(x, y, r, 0, 2*math.Pi)
+	ctx.Clip()
+}
+
+// ClipRoundRect intersects the clip region with a rectangle whose corners are rounded by r.
+func (ctx *Context2D) ClipRoundRect(x, y, width, height, r float64) {
+	ctx.BeginPath()
+	roundRectPath(ctx, x, y, width, height, r)
+	ctx.Clip()
+}
+
+// WithClip runs fn with the clip region intersected with region, restoring the
+// previous clip region and the rest of the state afterwards, even if fn panics.
+func (ctx *Context2D) WithClip(region Clipper, fn func(ctx *Context2D)) {
+	ctx.Save()
+	defer ctx.Restore()
+	region.ClipOn(ctx)
+	fn(ctx)
+}
+
+// ClipOn implements Clipper.
+func (r Rect) ClipOn(ctx *Context2D) {
+	ctx.ClipRect(r.X, r.Y, r.W, r.H)
+}
+
+// ClipOn implements Clipper.
+func (r RoundRect) ClipOn(ctx *Context2D) {
+	ctx.ClipRoundRect(r.X, r.Y, r.W, r.H, r.R)
+}
+
+// ClipOn implements Clipper.
+func (c Circle) ClipOn(ctx *Context2D) {
+	ctx.ClipCircle(c.X, c.Y, c.R)
+}
+
+// ClipOn implements Clipper using the even-odd rule, like Contains.
+func (poly Polygon) ClipOn(ctx *Context2D) {
+	ctx.BeginPath()
+	tracePolyline(ctx, poly)
+	ctx.ClosePath()
+	ctx.Call("clip", "evenodd")
+}
+
+// ClipOn implements Clipper.
+func (s PathShape) ClipOn(ctx *Context2D) {
+	ctx.ClipPath(s.Path)
+}
+
+// ClipOn implements Clipper.
+func (s *Selection) ClipOn(ctx *Context2D) {
+	s.Clip(ctx)
+}
+
+// roundRectPath adds a rectangle with corners rounded by r to the current path,
+// limiting r to half the shorter side.
+func roundRectPath(ctx *Context2D, x, y, w, h, r float64) {
+	r = math.Min(r, math.Min(math.Abs(w), math.Abs(h))/2)
+	if r <= 0 {
+		ctx.Rect(x, y, w, h)
+		return
+	}
+	ctx.MoveTo(x+r, y)
+	ctx.ArcTo(x+w, y, x+w, y+h, r)
+	ctx.ArcTo(x+w, y+h, x, y+h, r)
+	ctx.ArcTo(x, y+h, x, y, r)
+	ctx.ArcTo(x, y, x+w, y, r)
+	ctx.ClosePath()
+}
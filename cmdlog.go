@@ -0,0 +1,409 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Command is a recorded drawing call. Op is the name of the context method, or the
+// property name prefixed with "=" for property assignments; Args and Str hold its
+// numeric and string arguments.
+type Command struct {
+	Op   string    `json:"op"`
+	Args []float64 `json:"a,omitempty"`
+	Str  string    `json:"s,omitempty"`
+}
+
+// CommandLog is a sequence of recorded drawing calls. It encodes to JSON with
+// encoding/json and to a compact binary form with MarshalBinary.
+type CommandLog []Command
+
+// Replay runs the commands in order on ctx.
+func (l CommandLog) Replay(ctx *Context2D) {
+	for _, c := range l {
+		c.apply(ctx)
+	}
+}
+
+// ErrCommandNotAllowed is returned by ReplayChecked for commands a RecordingContext
+// cannot produce.
+var ErrCommandNotAllowed = errors.New("canvas: command not allowed")
+
+// replayOps holds the operations a RecordingContext records, and so the only ones
+// ReplayChecked accepts.
+var replayOps = map[string]bool{
+	"save": true, "restore": true,
+	"scale": true, "rotate": true, "translate": true, "transform": true, "setTransform": true,
+	"beginPath": true, "closePath": true, "moveTo": true, "lineTo": true,
+	"quadraticCurveTo": true, "bezierCurveTo": true, "arc": true, "arcTo": true, "rect": true,
+	"fill": true, "stroke": true, "clip": true,
+	"fillRect": true, "strokeRect": true, "clearRect": true,
+	"fillText": true, "strokeText": true, "setLineDash": true,
+	"=globalAlpha": true, "=lineDashOffset": true, "=lineWidth": true, "=miterLimit": true,
+	"=shadowBlur": true, "=shadowOffsetX": true, "=shadowOffsetY": true,
+	"=fillStyle": true, "=strokeStyle": true, "=shadowColor": true, "=font": true,
+	"=globalCompositeOperation": true, "=lineCap": true, "=lineJoin": true,
+	"=textAlign": true, "=textBaseline": true,
+}
+
+// ReplayChecked replays a log from an untrusted source such as a RemoteSender. It
+// rejects the whole log with ErrCommandNotAllowed if it contains operations a
+// RecordingContext cannot produce, and turns an exception thrown by the browser into
+// an error instead of a panic; the commands before the failing one have been drawn then.
+func (l CommandLog) ReplayChecked(ctx *Context2D) (err error) {
+	for _, c := range l {
+		if !replayOps[c.Op] {
+			return fmt.Errorf("%w: %q", ErrCommandNotAllowed, c.Op)
+		}
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			jsErr, ok := e.(*js.Error)
+			if !ok {
+				panic(e)
+			}
+			err = fmt.Errorf("canvas: replaying command log failed: %v", jsErr)
+		}
+	}()
+	l.Replay(ctx)
+	return nil
+}
+
+func (c Command) apply(ctx *Context2D) {
+	if len(c.Op) > 1 && c.Op[0] == '=' {
+		prop := c.Op[1:]
+		if len(c.Args) > 0 {
+			ctx.Set(prop, c.Args[0])
+		} else {
+			ctx.Set(prop, c.Str)
+		}
+		return
+	}
+	switch c.Op {
+	case "setLineDash":
+		ctx.SetLineDash(c.Args...)
+	case "fillText", "strokeText":
+		args := []interface{}{c.Str}
+		for _, a := range c.Args {
+			args = append(args, a)
+		}
+		ctx.Call(c.Op, args...)
+	default:
+		args := make([]interface{}, len(c.Args))
+		for i, a := range c.Args {
+			args[i] = a
+		}
+		ctx.Call(c.Op, args...)
+	}
+}
+
+var cmdLogMagic = []byte("CLG1")
+
+// ErrBadCommandLog is returned by UnmarshalBinary for data not produced by MarshalBinary.
+var ErrBadCommandLog = errors.New("canvas: malformed command log")
+
+// MarshalBinary encodes the log compactly: operation names are stored once and
+// referenced by index afterwards, numbers are stored as float64.
+func (l CommandLog) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	var tmp [binary.MaxVarintLen64]byte
+	uvarint := func(v uint64) {
+		buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+	}
+	str := func(s string) {
+		uvarint(uint64(len(s)))
+		buf.WriteString(s)
+	}
+	buf.Write(cmdLogMagic)
+	uvarint(uint64(len(l)))
+	ops := map[string]uint64{}
+	for _, c := range l {
+		i, ok := ops[c.Op]
+		if !ok {
+			i = uint64(len(ops))
+			ops[c.Op] = i
+		}
+		uvarint(i)
+		if !ok {
+			str(c.Op)
+		}
+		uvarint(uint64(len(c.Args)))
+		for _, a := range c.Args {
+			binary.LittleEndian.PutUint64(tmp[:8], math.Float64bits(a))
+			buf.Write(tmp[:8])
+		}
+		str(c.Str)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a log produced by MarshalBinary.
+func (l *CommandLog) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	magic := make([]byte, len(cmdLogMagic))
+	if _, err := r.Read(magic); err != nil || !bytes.Equal(magic, cmdLogMagic) {
+		return ErrBadCommandLog
+	}
+	uvarint := func() (uint64, error) {
+		return binary.ReadUvarint(r)
+	}
+	str := func() (string, error) {
+		n, err := uvarint()
+		if err != nil || n > uint64(r.Len()) {
+			return "", ErrBadCommandLog
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return "", io.ErrUnexpectedEOF
+		}
+		return string(b), nil
+	}
+	n, err := uvarint()
+	if err != nil || n > uint64(r.Len()) {
+		return ErrBadCommandLog
+	}
+	log := make(CommandLog, 0, n)
+	var ops []string
+	for k := uint64(0); k < n; k++ {
+		var c Command
+		i, err := uvarint()
+		if err != nil || i > uint64(len(ops)) {
+			return ErrBadCommandLog
+		}
+		if i == uint64(len(ops)) {
+			op, err := str()
+			if err != nil {
+				return err
+			}
+			ops = append(ops, op)
+		}
+		c.Op = ops[i]
+		na, err := uvarint()
+		if err != nil || na > uint64(r.Len())/8 {
+			return ErrBadCommandLog
+		}
+		if na > 0 {
+			c.Args = make([]float64, na)
+			var b [8]byte
+			for j := range c.Args {
+				if _, err := io.ReadFull(r, b[:]); err != nil {
+					return io.ErrUnexpectedEOF
+				}
+				c.Args[j] = math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+			}
+		}
+		if c.Str, err = str(); err != nil {
+			return err
+		}
+		log = append(log, c)
+	}
+	*l = log
+	return nil
+}
+
+// RecordingContext records drawing calls into a CommandLog, forwarding them to a
+// target context if it has one. Only calls that can be serialized are offered:
+// styles are CSS strings and images cannot be recorded.
+type RecordingContext struct {
+	// Target, if not nil, receives every call as it is recorded.
+	Target *Context2D
+	// Log holds the recorded commands.
+	Log CommandLog
+}
+
+// NewRecordingContext creates a RecordingContext forwarding to target, which may be nil
+// to record without drawing.
+func NewRecordingContext(target *Context2D) *RecordingContext {
+	return &RecordingContext{Target: target}
+}
+
+// Reset empties the log.
+func (r *RecordingContext) Reset() {
+	r.Log = r.Log[:0]
+}
+
+func (r *RecordingContext) record(c Command) {
+	r.Log = append(r.Log, c)
+	if r.Target != nil {
+		c.apply(r.Target)
+	}
+}
+
+func (r *RecordingContext) call(op string, args ...float64) {
+	r.record(Command{Op: op, Args: args})
+}
+
+func (r *RecordingContext) setString(prop, v string) {
+	r.record(Command{Op: "=" + prop, Str: v})
+}
+
+func (r *RecordingContext) setNumber(prop string, v float64) {
+	r.record(Command{Op: "=" + prop, Args: []float64{v}})
+}
+
+// Save records Context2D.Save.
+func (r *RecordingContext) Save() { r.call("save") }
+
+// Restore records Context2D.Restore.
+func (r *RecordingContext) Restore() { r.call("restore") }
+
+// Scale records Context2D.Scale.
+func (r *RecordingContext) Scale(x, y float64) { r.call("scale", x, y) }
+
+// Rotate records Context2D.Rotate.
+func (r *RecordingContext) Rotate(angle float64) { r.call("rotate", angle) }
+
+// Translate records Context2D.Translate.
+func (r *RecordingContext) Translate(x, y float64) { r.call("translate", x, y) }
+
+// Transform records Context2D.Transform.
+func (r *RecordingContext) Transform(a, b, c, d, e, f float64) {
+	r.call("transform", a, b, c, d, e, f)
+}
+
+// SetTransform records Context2D.SetTransform.
+func (r *RecordingContext) SetTransform(a, b, c, d, e, f float64) {
+	r.call("setTransform", a, b, c, d, e, f)
+}
+
+// BeginPath records Context2D.BeginPath.
+func (r *RecordingContext) BeginPath() { r.call("beginPath") }
+
+// ClosePath records Context2D.ClosePath.
+func (r *RecordingContext) ClosePath() { r.call("closePath") }
+
+// MoveTo records Context2D.MoveTo.
+func (r *RecordingContext) MoveTo(x, y float64) { r.call("moveTo", x, y) }
+
+// LineTo records Context2D.LineTo.
+func (r *RecordingContext) LineTo(x, y float64) { r.call("lineTo", x, y) }
+
+// QuadraticCurveTo records Context2D.QuadraticCurveTo.
+func (r *RecordingContext) QuadraticCurveTo(cpx, cpy, x, y float64) {
+	r.call("quadraticCurveTo", cpx, cpy, x, y)
+}
+
+// BezierCurveTo records Context2D.BezierCurveTo.
+func (r *RecordingContext) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) {
+	r.call("bezierCurveTo", cp1x, cp1y, cp2x, cp2y, x, y)
+}
+
+// Arc records Context2D.Arc.
+func (r *RecordingContext) Arc(x, y, radius, sAngle, eAngle float64, counterclockwise ...bool) {
+	ccw := 0.0
+	if len(counterclockwise) > 0 && counterclockwise[0] {
+		ccw = 1
+	}
+	r.call("arc", x, y, radius, sAngle, eAngle, ccw)
+}
+
+// ArcTo records Context2D.ArcTo.
+func (r *RecordingContext) ArcTo(x1, y1, x2, y2, radius float64) {
+	r.call("arcTo", x1, y1, x2, y2, radius)
+}
+
+// Rect records Context2D.Rect.
+func (r *RecordingContext) Rect(x, y, width, height float64) {
+	r.call("rect", x, y, width, height)
+}
+
+// Fill records Context2D.Fill.
+func (r *RecordingContext) Fill() { r.call("fill") }
+
+// Stroke records Context2D.Stroke.
+func (r *RecordingContext) Stroke() { r.call("stroke") }
+
+// Clip records Context2D.Clip.
+func (r *RecordingContext) Clip() { r.call("clip") }
+
+// FillRect records Context2D.FillRect.
+func (r *RecordingContext) FillRect(x, y, width, height float64) {
+	r.call("fillRect", x, y, width, height)
+}
+
+// StrokeRect records Context2D.StrokeRect.
+func (r *RecordingContext) StrokeRect(x, y, width, height float64) {
+	r.call("strokeRect", x, y, width, height)
+}
+
+// ClearRect records Context2D.ClearRect.
+func (r *RecordingContext) ClearRect(x, y, width, height float64) {
+	r.call("clearRect", x, y, width, height)
+}
+
+// FillText records Context2D.FillText.
+func (r *RecordingContext) FillText(text string, x, y float64, maxWidth ...float64) {
+	r.text("fillText", text, x, y, maxWidth)
+}
+
+// StrokeText records Context2D.StrokeText.
+func (r *RecordingContext) StrokeText(text string, x, y float64, maxWidth ...float64) {
+	r.text("strokeText", text, x, y, maxWidth)
+}
+
+func (r *RecordingContext) text(op, text string, x, y float64, maxWidth []float64) {
+	args := []float64{x, y}
+	if len(maxWidth) > 0 && maxWidth[0] >= 0 {
+		args = append(args, maxWidth[0])
+	}
+	r.record(Command{Op: op, Args: args, Str: text})
+}
+
+// SetLineDash records Context2D.SetLineDash.
+func (r *RecordingContext) SetLineDash(distances ...float64) {
+	r.record(Command{Op: "setLineDash", Args: append([]float64(nil), distances...)})
+}
+
+// SetFillStyle records an assignment of a CSS color to FillStyle.
+func (r *RecordingContext) SetFillStyle(css string) { r.setString("fillStyle", css) }
+
+// SetStrokeStyle records an assignment of a CSS color to StrokeStyle.
+func (r *RecordingContext) SetStrokeStyle(css string) { r.setString("strokeStyle", css) }
+
+// SetLineWidth records an assignment to LineWidth.
+func (r *RecordingContext) SetLineWidth(w float64) { r.setNumber("lineWidth", w) }
+
+// SetLineDashOffset records an assignment to LineDashOffset.
+func (r *RecordingContext) SetLineDashOffset(o float64) { r.setNumber("lineDashOffset", o) }
+
+// SetMiterLimit records an assignment to MiterLimit.
+func (r *RecordingContext) SetMiterLimit(l float64) { r.setNumber("miterLimit", l) }
+
+// SetGlobalAlpha records an assignment to GlobalAlpha.
+func (r *RecordingContext) SetGlobalAlpha(a float64) { r.setNumber("globalAlpha", a) }
+
+// SetFont records an assignment to Font.
+func (r *RecordingContext) SetFont(font string) { r.setString("font", font) }
+
+// SetShadow records assignments of the shadow color, blur and offset.
+func (r *RecordingContext) SetShadow(color string, blur, offsetX, offsetY float64) {
+	r.setString("shadowColor", color)
+	r.setNumber("shadowBlur", blur)
+	r.setNumber("shadowOffsetX", offsetX)
+	r.setNumber("shadowOffsetY", offsetY)
+}
+
+// SetLineCap records Context2D.SetLineCap.
+func (r *RecordingContext) SetLineCap(c LineCap) { r.setString("lineCap", string(c)) }
+
+// SetLineJoin records Context2D.SetLineJoin.
+func (r *RecordingContext) SetLineJoin(j LineJoin) { r.setString("lineJoin", string(j)) }
+
+// SetTextAlign records Context2D.SetTextAlign.
+func (r *RecordingContext) SetTextAlign(a TextAlign) { r.setString("textAlign", string(a)) }
+
+// SetTextBaseline records Context2D.SetTextBaseline.
+func (r *RecordingContext) SetTextBaseline(b TextBaseline) {
+	r.setString("textBaseline", string(b))
+}
+
+// SetCompositeOp records Context2D.SetCompositeOp.
+func (r *RecordingContext) SetCompositeOp(op CompositeOp) {
+	r.setString("globalCompositeOperation", string(op))
+}
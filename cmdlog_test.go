@@ -0,0 +1,91 @@
+package canvas
+
+import (
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+var testCommandLog = CommandLog{
+	{Op: "save"},
+	{Op: "=fillStyle", Str: "#ff0000"},
+	{Op: "fillRect", Args: []float64{1, 2, 30.5, -4}},
+	{Op: "fillText", Args: []float64{10, 20}, Str: "héllo, wörld"},
+	{Op: "fillRect", Args: []float64{math.Inf(1), math.Inf(-1), math.MaxFloat64, math.SmallestNonzeroFloat64}},
+	{Op: "=lineWidth", Args: []float64{2}},
+	{Op: "restore"},
+}
+
+func TestCommandLogBinaryRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		log  CommandLog
+	}{
+		{"empty", CommandLog{}},
+		{"single", CommandLog{{Op: "beginPath"}}},
+		{"repeated ops", CommandLog{{Op: "lineTo", Args: []float64{1, 1}}, {Op: "lineTo", Args: []float64{2, 2}}, {Op: "lineTo", Args: []float64{3, 3}}}},
+		{"mixed", testCommandLog},
+	}
+	for _, tt := range tests {
+		data, err := tt.log.MarshalBinary()
+		if err != nil {
+			t.Errorf("%s: MarshalBinary: %v", tt.name, err)
+			continue
+		}
+		var got CommandLog
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Errorf("%s: UnmarshalBinary: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.log) {
+			t.Errorf("%s: round trip = %v, want %v", tt.name, got, tt.log)
+		}
+	}
+}
+
+func TestCommandLogBinaryNaN(t *testing.T) {
+	data, _ := CommandLog{{Op: "moveTo", Args: []float64{math.NaN(), 1}}}.MarshalBinary()
+	var got CommandLog
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if len(got) != 1 || len(got[0].Args) != 2 || !math.IsNaN(got[0].Args[0]) || got[0].Args[1] != 1 {
+		t.Errorf("round trip = %v, want [moveTo NaN 1]", got)
+	}
+}
+
+func TestCommandLogBinaryTruncated(t *testing.T) {
+	data, _ := testCommandLog.MarshalBinary()
+	for n := 0; n < len(data); n++ {
+		orig := CommandLog{{Op: "keep"}}
+		l := orig
+		err := l.UnmarshalBinary(data[:n])
+		if err != ErrBadCommandLog && err != io.ErrUnexpectedEOF {
+			t.Errorf("truncated to %d of %d bytes: err = %v, want ErrBadCommandLog or io.ErrUnexpectedEOF", n, len(data), err)
+		}
+		if !reflect.DeepEqual(l, orig) {
+			t.Errorf("truncated to %d bytes: log changed to %v on error", n, l)
+		}
+	}
+}
+
+func TestCommandLogBinaryMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"wrong magic", []byte("CLG2\x00")},
+		{"count beyond data", []byte("CLG1\x05\x00")},
+		{"op index out of range", []byte("CLG1\x01\x02")},
+		{"op name too long", []byte("CLG1\x01\x00\x09ab")},
+		{"arg count beyond data", []byte("CLG1\x01\x00\x01x\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")},
+		{"overlong uvarint", []byte("CLG1\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01")},
+	}
+	for _, tt := range tests {
+		var l CommandLog
+		if err := l.UnmarshalBinary(tt.data); err != ErrBadCommandLog {
+			t.Errorf("%s: err = %v, want ErrBadCommandLog", tt.name, err)
+		}
+	}
+}
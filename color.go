@@ -0,0 +1,140 @@
+package canvas
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Style is a fill or stroke style: a CSS color string, a color.Color, a *Gradient,
+// a *Pattern or a raw *js.Object.
+type Style interface{}
+
+// jsStyle converts s into a value assignable to fillStyle or strokeStyle.
+func jsStyle(s Style) interface{} {
+	switch v := s.(type) {
+	case string, *js.Object:
+		return v
+	case *Gradient:
+		return v.Value()
+	case *Pattern:
+		return v.Value()
+	case color.Color:
+		return cssColor(v)
+	}
+	return s
+}
+
+// cssColor formats c as a CSS color string.
+func cssColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	rgb := strconv.Itoa(int(n.R)) + "," + strconv.Itoa(int(n.G)) + "," + strconv.Itoa(int(n.B))
+	if n.A == 0xff {
+		return "rgb(" + rgb + ")"
+	}
+	return "rgba(" + rgb + "," + strconv.FormatFloat(float64(n.A)/0xff, 'f', 3, 64) + ")"
+}
+
+// Lerp interpolates linearly between c1 and c2 in straight alpha sRGB space,
+// returning c1 for t <= 0 and c2 for t >= 1.
+func Lerp(c1, c2 color.Color, t float64) color.NRGBA {
+	a := color.NRGBAModel.Convert(c1).(color.NRGBA)
+	b := color.NRGBAModel.Convert(c2).(color.NRGBA)
+	if t <= 0 {
+		return a
+	}
+	if t >= 1 {
+		return b
+	}
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + t*(float64(y)-float64(x))))
+	}
+	return color.NRGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
+
+// ColorMap maps a value between 0 and 1 to a color, e.g. for heatmaps.
+type ColorMap interface {
+	At(t float64) color.NRGBA
+}
+
+// ColorMapFunc adapts a function to ColorMap.
+type ColorMapFunc func(t float64) color.NRGBA
+
+// At calls f(t).
+func (f ColorMapFunc) At(t float64) color.NRGBA {
+	return f(t)
+}
+
+// Palette is a ColorMap interpolating between stops sorted by offset.
+type Palette []Stop
+
+// At returns the color at t, interpolating between the surrounding stops.
+func (p Palette) At(t float64) color.NRGBA {
+	if len(p) == 0 {
+		return color.NRGBA{}
+	}
+	if t <= p[0].Offset {
+		return color.NRGBAModel.Convert(p[0].Color).(color.NRGBA)
+	}
+	for i := 1; i < len(p); i++ {
+		if t <= p[i].Offset {
+			a, b := p[i-1], p[i]
+			return Lerp(a.Color, b.Color, (t-a.Offset)/(b.Offset-a.Offset))
+		}
+	}
+	return color.NRGBAModel.Convert(p[len(p)-1].Color).(color.NRGBA)
+}
+
+// Sample returns n colors evenly spaced over [0, 1] of m, e.g. to build a lookup table.
+func Sample(m ColorMap, n int) []color.NRGBA {
+	out := make([]color.NRGBA, n)
+	for i := range out {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		out[i] = m.At(t)
+	}
+	return out
+}
+
+func evenPalette(colors ...color.NRGBA) Palette {
+	p := make(Palette, len(colors))
+	for i, c := range colors {
+		p[i] = Stop{Offset: float64(i) / float64(len(colors)-1), Color: c}
+	}
+	return p
+}
+
+// Common color maps.
+var (
+	// Grayscale runs from black to white.
+	Grayscale = evenPalette(color.NRGBA{0, 0, 0, 0xff}, color.NRGBA{0xff, 0xff, 0xff, 0xff})
+	// Viridis is the perceptually uniform color map of matplotlib.
+	Viridis = evenPalette(
+		color.NRGBA{0x44, 0x01, 0x54, 0xff},
+		color.NRGBA{0x47, 0x2d, 0x7b, 0xff},
+		color.NRGBA{0x3b, 0x52, 0x8b, 0xff},
+		color.NRGBA{0x2c, 0x72, 0x8e, 0xff},
+		color.NRGBA{0x21, 0x91, 0x8c, 0xff},
+		color.NRGBA{0x28, 0xae, 0x80, 0xff},
+		color.NRGBA{0x5e, 0xc9, 0x62, 0xff},
+		color.NRGBA{0xad, 0xdc, 0x30, 0xff},
+		color.NRGBA{0xfd, 0xe7, 0x25, 0xff},
+	)
+	// Turbo is Google's improved rainbow color map, computed with its polynomial approximation.
+	Turbo = ColorMapFunc(turbo)
+)
+
+func turbo(t float64) color.NRGBA {
+	t = math.Max(0, math.Min(1, t))
+	r := 0.13572138 + t*(4.61539260+t*(-42.66032258+t*(132.13108234+t*(-152.94239396+t*59.28637943))))
+	g := 0.09140261 + t*(2.19418839+t*(4.84296658+t*(-14.18503333+t*(4.27729857+t*2.82956604))))
+	b := 0.10667330 + t*(12.64194608+t*(-60.58204836+t*(110.36276771+t*(-89.90310912+t*27.34824973))))
+	ch := func(v float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, v)) * 0xff))
+	}
+	return color.NRGBA{ch(r), ch(g), ch(b), 0xff}
+}
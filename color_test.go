@@ -0,0 +1,70 @@
+package canvas
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestPaletteAt(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 0xff}
+	white := color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	blue := color.NRGBA{0, 0, 0xff, 0xff}
+	uneven := Palette{{0.25, black}, {0.5, white}, {1, red}}
+	// a hard edge at 0.5, from two stops at the same offset
+	edge := Palette{{0, red}, {0.5, red}, {0.5, blue}, {1, blue}}
+	tests := []struct {
+		name string
+		p    Palette
+		t    float64
+		want color.NRGBA
+	}{
+		{"empty", nil, 0.5, color.NRGBA{}},
+		{"single stop", Palette{{0.5, red}}, 0.9, red},
+		{"start", Grayscale, 0, black},
+		{"end", Grayscale, 1, white},
+		{"middle", Grayscale, 0.5, color.NRGBA{0x80, 0x80, 0x80, 0xff}},
+		{"quarter", Grayscale, 0.25, color.NRGBA{0x40, 0x40, 0x40, 0xff}},
+		{"below range", Grayscale, -1, black},
+		{"above range", Grayscale, 2, white},
+		{"before first stop", uneven, 0.1, black},
+		{"between uneven stops", uneven, 0.375, color.NRGBA{0x80, 0x80, 0x80, 0xff}},
+		{"on inner stop", uneven, 0.5, white},
+		{"last segment", uneven, 0.75, color.NRGBA{0xff, 0x80, 0x80, 0xff}},
+		{"on hard edge", edge, 0.5, red},
+		{"after hard edge", edge, 0.5001, blue},
+		{"alpha", Palette{{0, color.NRGBA{0xff, 0, 0, 0}}, {1, red}}, 0.5, color.NRGBA{0xff, 0, 0, 0x80}},
+		{"premultiplied stop", Palette{{0, color.RGBA{0x40, 0, 0, 0x80}}}, 0, color.NRGBA{0x7f, 0, 0, 0x80}},
+		{"viridis end", Viridis, 1, color.NRGBA{0xfd, 0xe7, 0x25, 0xff}},
+	}
+	for _, tt := range tests {
+		if got := tt.p.At(tt.t); got != tt.want {
+			t.Errorf("%s: At(%v) = %v, want %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 0xff}
+	white := color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	tests := []struct {
+		name string
+		m    ColorMap
+		n    int
+		want []color.NRGBA
+	}{
+		{"none", Grayscale, 0, []color.NRGBA{}},
+		{"one takes the start", Grayscale, 1, []color.NRGBA{black}},
+		{"two takes the ends", Grayscale, 2, []color.NRGBA{black, white}},
+		{"three", Grayscale, 3, []color.NRGBA{black, {0x80, 0x80, 0x80, 0xff}, white}},
+		{"five", Grayscale, 5, []color.NRGBA{black, {0x40, 0x40, 0x40, 0xff}, {0x80, 0x80, 0x80, 0xff}, {0xbf, 0xbf, 0xbf, 0xff}, white}},
+		{"func", ColorMapFunc(func(t float64) color.NRGBA { return color.NRGBA{A: uint8(t * 100)} }), 3,
+			[]color.NRGBA{{A: 0}, {A: 50}, {A: 100}}},
+	}
+	for _, tt := range tests {
+		if got := Sample(tt.m, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Sample(%d) = %v, want %v", tt.name, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package canvas
+
+// Layer is a logical layer of a Compositor, backed by an offscreen canvas.
+type Layer struct {
+	Name string
+	// Canvas holds the pixels of the layer.
+	Canvas *Canvas
+	// Opacity between 0 and 1 applied when compositing.
+	Opacity float64
+	// Visible layers take part in compositing.
+	Visible bool
+	// Blend is the composite or blend mode used to draw the layer onto those below;
+	// source-over if empty.
+	Blend CompositeOp
+}
+
+// Context returns the 2D context of the layer.
+func (l *Layer) Context() *Context2D {
+	return l.Canvas.GetContext2D()
+}
+
+// Compositor keeps an ordered list of offscreen layers of the same size and
+// composites them onto a visible canvas with per-layer opacity, visibility and
+// blend mode, like the layers panel of a paint program.
+type Compositor struct {
+	layers        []*Layer
+	width, height int
+}
+
+// NewCompositor creates a compositor without layers whose layers are width x height pixels.
+func NewCompositor(width, height int) *Compositor {
+	return &Compositor{width: width, height: height}
+}
+
+// Add creates a transparent, visible, opaque layer on top of the others.
+func (c *Compositor) Add(name string) *Layer {
+	return c.Insert(len(c.layers), name)
+}
+
+// Insert creates a layer at index i, 0 being the bottom.
+func (c *Compositor) Insert(i int, name string) *Layer {
+	l := &Layer{Name: name, Canvas: Create(c.width, c.height), Opacity: 1, Visible: true}
+	if i < 0 {
+		i = 0
+	}
+	if i > len(c.layers) {
+		i = len(c.layers)
+	}
+	c.layers = append(c.layers, nil)
+	copy(c.layers[i+1:], c.layers[i:])
+	c.layers[i] = l
+	return l
+}
+
+// Layer returns the topmost layer with the given name, nil if there is none.
+func (c *Compositor) Layer(name string) *Layer {
+	if i := c.Index(name); i >= 0 {
+		return c.layers[i]
+	}
+	return nil
+}
+
+// Index returns the position of the topmost layer with the given name, -1 if there is none.
+func (c *Compositor) Index(name string) int {
+	for i := len(c.layers) - 1; i >= 0; i-- {
+		if c.layers[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Layers returns the layers, bottom first. The slice must not be modified.
+func (c *Compositor) Layers() []*Layer {
+	return c.layers
+}
+
+// Remove deletes the named layer.
+func (c *Compositor) Remove(name string) {
+	if i := c.Index(name); i >= 0 {
+		c.layers = append(c.layers[:i], c.layers[i+1:]...)
+	}
+}
+
+// Move moves the named layer to index i, 0 being the bottom.
+func (c *Compositor) Move(name string, i int) {
+	from := c.Index(name)
+	if from < 0 {
+		return
+	}
+	l := c.layers[from]
+	c.layers = append(c.layers[:from], c.layers[from+1:]...)
+	if i < 0 {
+		i = 0
+	}
+	if i > len(c.layers) {
+		i = len(c.layers)
+	}
+	c.layers = append(c.layers, nil)
+	copy(c.layers[i+1:], c.layers[i:])
+	c.layers[i] = l
+}
+
+// Size returns the size of the layers in pixels.
+func (c *Compositor) Size() (width, height int) {
+	return c.width, c.height
+}
+
+// Resize changes the size of all layers, keeping their content anchored at the top left corner.
+func (c *Compositor) Resize(width, height int) {
+	for _, l := range c.layers {
+		old := l.Canvas
+		l.Canvas = Create(width, height)
+		l.Canvas.GetContext2D().DrawImage(old, 0, 0, float64(c.width), float64(c.height))
+	}
+	c.width, c.height = width, height
+}
+
+// Composite draws the visible layers, bottom first, onto ctx at x, y using the
+// current transform of ctx. The destination is not cleared first.
+func (c *Compositor) Composite(ctx *Context2D, x, y float64) {
+	ctx.Save()
+	defer ctx.Restore()
+	alpha := ctx.GlobalAlpha
+	for _, l := range c.layers {
+		if !l.Visible || l.Opacity <= 0 {
+			continue
+		}
+		ctx.GlobalAlpha = alpha * l.Opacity
+		op := l.Blend
+		if op == "" {
+			op = CompositeSourceOver
+		}
+		ctx.SetCompositeOp(op)
+		ctx.DrawImage(l.Canvas, x, y, float64(c.width), float64(c.height))
+	}
+}
+
+// Flatten returns a new canvas with the visible layers composited onto transparency.
+func (c *Compositor) Flatten() *Canvas {
+	out := Create(c.width, c.height)
+	c.Composite(out.GetContext2D(), 0, 0)
+	return out
+}
@@ -0,0 +1,110 @@
+package canvas
+
+import "math"
+
+// Dash splits polylines into dashes following pattern, a list of alternating dash and gap lengths
+// as accepted by SetLineDash, shifted by offset like LineDashOffset.
+// As on a canvas the pattern restarts on every polyline, and an odd-length pattern is repeated once.
+// An empty or all-zero pattern returns the polylines unchanged, and so does a pattern
+// with a negative, NaN or infinite entry, which setLineDash ignores too.
+// A non-finite offset is treated as 0.
+func Dash(lines [][]Point, pattern []float64, offset float64) [][]Point {
+	for _, d := range pattern {
+		if d < 0 || math.IsNaN(d) || math.IsInf(d, 0) {
+			return lines
+		}
+	}
+	if math.IsNaN(offset) || math.IsInf(offset, 0) {
+		offset = 0
+	}
+	if len(pattern)%2 == 1 {
+		pattern = append(append([]float64(nil), pattern...), pattern...)
+	}
+	var period float64
+	for _, d := range pattern {
+		period += d
+	}
+	if period <= 0 {
+		return lines
+	}
+	var dashes [][]Point
+	for _, line := range lines {
+		if len(line) < 2 {
+			continue
+		}
+		// Find the position in the pattern at the start of the line.
+		pos := math.Mod(offset, period)
+		if pos < 0 {
+			pos += period
+		}
+		idx := 0
+		// a zero length dash at the very start still draws a dot
+		for pos > 0 && pos >= pattern[idx] {
+			pos -= pattern[idx]
+			idx = (idx + 1) % len(pattern)
+		}
+		remaining := pattern[idx] - pos
+		var cur []Point
+		if idx%2 == 0 {
+			cur = []Point{line[0]}
+		}
+		for i := 1; i < len(line); i++ {
+			a, b := line[i-1], line[i]
+			segLen := math.Hypot(b.X-a.X, b.Y-a.Y)
+			travelled := 0.0
+			for segLen-travelled > remaining {
+				travelled += remaining
+				p := a.Add(b.Sub(a).Mul(travelled / segLen))
+				if idx%2 == 0 {
+					dashes = append(dashes, append(cur, p))
+					cur = nil
+				} else {
+					cur = []Point{p}
+				}
+				idx = (idx + 1) % len(pattern)
+				remaining = pattern[idx]
+			}
+			remaining -= segLen - travelled
+			if idx%2 == 0 {
+				cur = append(cur, b)
+			}
+		}
+		if idx%2 == 0 && len(cur) > 1 {
+			dashes = append(dashes, cur)
+		}
+	}
+	return dashes
+}
+
+// StrokeDashed strokes path with the dash pattern computed in Go instead of by SetLineDash,
+// flattening curves with the given tolerance. If style is not nil it is called before each dash
+// is stroked, with the index of the dash, so dashes can be styled individually
+// (e.g. alternating colors); otherwise all dashes are stroked in one call.
+func (ctx *Context2D) StrokeDashed(path *Path, pattern []float64, offset, tolerance float64, style func(ctx *Context2D, i int)) {
+	dashes := Dash(path.Flatten(tolerance), pattern, offset)
+	if style == nil {
+		ctx.BeginPath()
+		for _, d := range dashes {
+			tracePolyline(ctx, d)
+		}
+		ctx.Stroke()
+		return
+	}
+	for i, d := range dashes {
+		ctx.BeginPath()
+		tracePolyline(ctx, d)
+		style(ctx, i)
+		ctx.Stroke()
+	}
+}
+
+// tracePolyline adds pts as an open sub-path to the current path.
+func tracePolyline(ctx *Context2D, pts []Point) {
+	if len(pts) == 0 {
+		return
+	}
+	ctx.MoveTo(pts[0].X, pts[0].Y)
+	for _, p := range pts[1:] {
+		ctx.LineTo(p.X, p.Y)
+	}
+}
@@ -0,0 +1,65 @@
+package canvas
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDash(t *testing.T) {
+	line := [][]Point{{{0, 0}, {10, 0}}}
+	tests := []struct {
+		name    string
+		lines   [][]Point
+		pattern []float64
+		offset  float64
+		want    [][]Point
+	}{
+		{"even pattern", line, []float64{2, 3}, 0, [][]Point{{{0, 0}, {2, 0}}, {{5, 0}, {7, 0}}}},
+		{"odd pattern repeats", line, []float64{3}, 0, [][]Point{{{0, 0}, {3, 0}}, {{6, 0}, {9, 0}}}},
+		{"odd pattern of three", line, []float64{1, 2, 3}, 0, [][]Point{{{0, 0}, {1, 0}}, {{3, 0}, {6, 0}}, {{7, 0}, {9, 0}}}},
+		{"positive offset", line, []float64{2, 3}, 1, [][]Point{{{0, 0}, {1, 0}}, {{4, 0}, {6, 0}}, {{9, 0}, {10, 0}}}},
+		{"negative offset", line, []float64{2, 3}, -1, [][]Point{{{1, 0}, {3, 0}}, {{6, 0}, {8, 0}}}},
+		{"offset of whole periods", line, []float64{2, 3}, 10, [][]Point{{{0, 0}, {2, 0}}, {{5, 0}, {7, 0}}}},
+		{"around a corner", [][]Point{{{0, 0}, {4, 0}, {4, 4}}}, []float64{3, 2}, 0, [][]Point{{{0, 0}, {3, 0}}, {{4, 1}, {4, 4}}}},
+		{"restarts per line", [][]Point{{{0, 0}, {3, 0}}, {{0, 5}, {3, 5}}}, []float64{2, 3}, 0, [][]Point{{{0, 0}, {2, 0}}, {{0, 5}, {2, 5}}}},
+		{"single point line dropped", [][]Point{{{1, 1}}}, []float64{2, 3}, 0, nil},
+		{"empty pattern", line, nil, 0, line},
+		{"all zero pattern", line, []float64{0, 0}, 0, line},
+		{"negative entry", line, []float64{2, -1}, 0, line},
+		{"NaN entry", line, []float64{2, math.NaN()}, 0, line},
+		{"infinite entry", line, []float64{math.Inf(1), 2}, 0, line},
+		{"NaN offset", line, []float64{2, 3}, math.NaN(), [][]Point{{{0, 0}, {2, 0}}, {{5, 0}, {7, 0}}}},
+	}
+	for _, tt := range tests {
+		if got := Dash(tt.lines, tt.pattern, tt.offset); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDashZeroEntries(t *testing.T) {
+	line := [][]Point{{{0, 0}, {10, 0}}}
+
+	// zero length dashes still produce dots, as caps are drawn for them on a canvas
+	dots := Dash(line, []float64{0, 2}, 0)
+	if len(dots) != 5 {
+		t.Fatalf("got %d dots, want 5: %v", len(dots), dots)
+	}
+	for i, d := range dots {
+		if want := (Point{X: float64(2 * i)}); d[0] != want || d[len(d)-1] != want {
+			t.Errorf("dot %d = %v, want a single point at %v", i, d, want)
+		}
+	}
+
+	// zero gaps cover the whole line
+	var total float64
+	for _, d := range Dash(line, []float64{2, 0}, 0) {
+		for i := 1; i < len(d); i++ {
+			total += math.Hypot(d[i].X-d[i-1].X, d[i].Y-d[i-1].Y)
+		}
+	}
+	if total != 10 {
+		t.Errorf("zero gaps: dashes cover %v, want 10", total)
+	}
+}
@@ -0,0 +1,43 @@
+package canvas
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrBadDataURL is returned by DecodeDataURL for strings that aren't data URLs.
+var ErrBadDataURL = errors.New("canvas: malformed data URL")
+
+// DecodeDataURL splits a data URL such as the result of ToDataURL into its mime type,
+// without parameters, and the decoded bytes. Both base64 and percent encoded payloads
+// are accepted; a URL without a mime type has the type "text/plain".
+// The bytes of an image can be passed on to image.Decode or uploaded as they are.
+func DecodeDataURL(s string) (mime string, data []byte, err error) {
+	if len(s) < 5 || !strings.EqualFold(s[:5], "data:") {
+		return "", nil, ErrBadDataURL
+	}
+	header, payload, ok := strings.Cut(s[5:], ",")
+	if !ok {
+		return "", nil, ErrBadDataURL
+	}
+	params := strings.Split(header, ";")
+	mime = strings.ToLower(strings.TrimSpace(params[0]))
+	if mime == "" {
+		mime = "text/plain"
+	}
+	if strings.EqualFold(params[len(params)-1], "base64") && len(params) > 1 {
+		payload = strings.TrimRight(payload, "=")
+		data, err = base64.RawStdEncoding.DecodeString(payload)
+		if err != nil {
+			return "", nil, ErrBadDataURL
+		}
+		return mime, data, nil
+	}
+	text, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, ErrBadDataURL
+	}
+	return mime, []byte(text), nil
+}
@@ -0,0 +1,43 @@
+package canvas
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeDataURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantMime string
+		wantData []byte
+		wantErr  error
+	}{
+		{"base64 png", "data:image/png;base64,iVBORw0KGgo=", "image/png", []byte("\x89PNG\r\n\x1a\n"), nil},
+		{"base64 unpadded", "data:text/plain;base64,aGVsbG8", "text/plain", []byte("hello"), nil},
+		{"base64 with params", "data:text/plain;charset=utf-8;base64,aGk=", "text/plain", []byte("hi"), nil},
+		{"base64 uppercase", "DATA:Image/JPEG;BASE64,aGk=", "image/jpeg", []byte("hi"), nil},
+		{"percent encoded", "data:text/plain,a%20b%2Cc", "text/plain", []byte("a b,c"), nil},
+		{"comma in payload", "data:text/csv,a,b,c", "text/csv", []byte("a,b,c"), nil},
+		{"params dropped", "data:text/html;charset=utf-8,%3Cp%3E", "text/html", []byte("<p>"), nil},
+		{"no mime", "data:,hello", "text/plain", []byte("hello"), nil},
+		{"no mime base64", "data:;base64,aGk=", "text/plain", []byte("hi"), nil},
+		{"bare base64 is a mime", "data:base64,aGk=", "base64", []byte("aGk="), nil},
+		{"empty payload", "data:image/png;base64,", "image/png", []byte{}, nil},
+		{"missing comma", "data:image/png;base64", "", nil, ErrBadDataURL},
+		{"not a data URL", "http://example.com/a.png", "", nil, ErrBadDataURL},
+		{"too short", "data", "", nil, ErrBadDataURL},
+		{"bad base64", "data:image/png;base64,@@@@", "", nil, ErrBadDataURL},
+		{"bad percent escape", "data:text/plain,100%", "", nil, ErrBadDataURL},
+	}
+	for _, tt := range tests {
+		mime, data, err := DecodeDataURL(tt.in)
+		if err != tt.wantErr {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if mime != tt.wantMime || !bytes.Equal(data, tt.wantData) {
+			t.Errorf("%s: DecodeDataURL(%q) = %q, %q, want %q, %q", tt.name, tt.in, mime, data, tt.wantMime, tt.wantData)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package canvas
+
+// StyleCache sits in front of a context and skips assignments of style properties
+// that would not change anything. Renderers that set the same style for every shape
+// save a bridge crossing per redundant assignment.
+//
+// The cache only knows about changes made through it: route Save and Restore through
+// the cache as well, and call Invalidate after changing the state by other means
+// (including SetState, or resizing the canvas, which resets the context).
+type StyleCache struct {
+	ctx   *Context2D
+	cur   cachedStyle
+	stack []cachedStyle
+	// Skipped counts the assignments that were skipped.
+	Skipped int
+}
+
+type cachedStyle struct {
+	fill, stroke   interface{}
+	lineWidth      float64
+	globalAlpha    float64
+	font           string
+	lineCap        LineCap
+	lineJoin       LineJoin
+	textAlign      TextAlign
+	textBaseline   TextBaseline
+	compositeOp    CompositeOp
+	lineDashOffset float64
+}
+
+// NewStyleCache creates a cache for ctx, starting from its current state.
+func NewStyleCache(ctx *Context2D) *StyleCache {
+	s := &StyleCache{ctx: ctx}
+	s.Invalidate()
+	return s
+}
+
+// Context returns the underlying context.
+func (s *StyleCache) Context() *Context2D {
+	return s.ctx
+}
+
+// Invalidate reads the current values back from the context, for use after the
+// state was changed behind the cache's back.
+func (s *StyleCache) Invalidate() {
+	ctx := s.ctx
+	s.cur = cachedStyle{
+		fill:           styleValue(ctx.Get("fillStyle")),
+		stroke:         styleValue(ctx.Get("strokeStyle")),
+		lineWidth:      ctx.LineWidth,
+		globalAlpha:    ctx.GlobalAlpha,
+		font:           ctx.Font,
+		lineCap:        ctx.LineCap,
+		lineJoin:       ctx.LineJoin,
+		textAlign:      ctx.TextAlign,
+		textBaseline:   ctx.TextBaseline,
+		compositeOp:    ctx.GlobalCompositeOperation,
+		lineDashOffset: ctx.LineDashOffset,
+	}
+}
+
+// Save saves the context state along with the cached values.
+func (s *StyleCache) Save() {
+	s.ctx.Save()
+	s.stack = append(s.stack, s.cur)
+}
+
+// Restore restores the context state along with the cached values.
+func (s *StyleCache) Restore() {
+	s.ctx.Restore()
+	if n := len(s.stack); n > 0 {
+		s.cur = s.stack[n-1]
+		s.stack = s.stack[:n-1]
+	}
+}
+
+// SetFillStyle sets the fill style unless it is already in effect.
+func (s *StyleCache) SetFillStyle(style Style) {
+	v := jsStyle(style)
+	if v == s.cur.fill {
+		s.Skipped++
+		return
+	}
+	s.ctx.FillStyle = v
+	s.cur.fill = v
+}
+
+// SetStrokeStyle sets the stroke style unless it is already in effect.
+func (s *StyleCache) SetStrokeStyle(style Style) {
+	v := jsStyle(style)
+	if v == s.cur.stroke {
+		s.Skipped++
+		return
+	}
+	s.ctx.StrokeStyle = v
+	s.cur.stroke = v
+}
+
+// SetLineWidth sets the line width unless it is already in effect.
+func (s *StyleCache) SetLineWidth(w float64) {
+	if w == s.cur.lineWidth {
+		s.Skipped++
+		return
+	}
+	s.ctx.LineWidth = w
+	s.cur.lineWidth = w
+}
+
+// SetGlobalAlpha sets the global alpha unless it is already in effect.
+func (s *StyleCache) SetGlobalAlpha(a float64) {
+	if a == s.cur.globalAlpha {
+		s.Skipped++
+		return
+	}
+	s.ctx.GlobalAlpha = a
+	s.cur.globalAlpha = a
+}
+
+// SetFont sets the font unless it is already in effect.
+func (s *StyleCache) SetFont(font string) {
+	if font == s.cur.font {
+		s.Skipped++
+		return
+	}
+	s.ctx.Font = font
+	s.cur.font = font
+}
+
+// SetLineCap sets the line cap unless it is already in effect.
+func (s *StyleCache) SetLineCap(c LineCap) {
+	if c == s.cur.lineCap {
+		s.Skipped++
+		return
+	}
+	s.ctx.SetLineCap(c)
+	s.cur.lineCap = c
+}
+
+// SetLineJoin sets the line join unless it is already in effect.
+func (s *StyleCache) SetLineJoin(j LineJoin) {
+	if j == s.cur.lineJoin {
+		s.Skipped++
+		return
+	}
+	s.ctx.SetLineJoin(j)
+	s.cur.lineJoin = j
+}
+
+// SetTextAlign sets the text alignment unless it is already in effect.
+func (s *StyleCache) SetTextAlign(a TextAlign) {
+	if a == s.cur.textAlign {
+		s.Skipped++
+		return
+	}
+	s.ctx.SetTextAlign(a)
+	s.cur.textAlign = a
+}
+
+// SetTextBaseline sets the text baseline unless it is already in effect.
+func (s *StyleCache) SetTextBaseline(b TextBaseline) {
+	if b == s.cur.textBaseline {
+		s.Skipped++
+		return
+	}
+	s.ctx.SetTextBaseline(b)
+	s.cur.textBaseline = b
+}
+
+// SetCompositeOp sets the composite operation unless it is already in effect.
+func (s *StyleCache) SetCompositeOp(op CompositeOp) {
+	if op == s.cur.compositeOp {
+		s.Skipped++
+		return
+	}
+	s.ctx.SetCompositeOp(op)
+	s.cur.compositeOp = op
+}
+
+// SetLineDashOffset sets the line dash offset unless it is already in effect.
+func (s *StyleCache) SetLineDashOffset(o float64) {
+	if o == s.cur.lineDashOffset {
+		s.Skipped++
+		return
+	}
+	s.ctx.LineDashOffset = o
+	s.cur.lineDashOffset = o
+}
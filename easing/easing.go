@@ -0,0 +1,183 @@
+// Package easing provides the standard easing functions used in animations.
+//
+// Every function maps the normalized time t in [0, 1] to an eased progress value,
+// with f(0) == 0 and f(1) == 1. Elastic and back functions overshoot that range in between.
+package easing
+
+import "math"
+
+// Func is an easing function.
+type Func func(t float64) float64
+
+// Linear returns t unchanged.
+func Linear(t float64) float64 { return t }
+
+// InQuad accelerates from zero velocity.
+func InQuad(t float64) float64 { return t * t }
+
+// OutQuad decelerates to zero velocity.
+func OutQuad(t float64) float64 { return t * (2 - t) }
+
+// InOutQuad accelerates until halfway, then decelerates.
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// InCubic accelerates from zero velocity.
+func InCubic(t float64) float64 { return t * t * t }
+
+// OutCubic decelerates to zero velocity.
+func OutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// InOutCubic accelerates until halfway, then decelerates.
+func InOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// InQuart accelerates from zero velocity.
+func InQuart(t float64) float64 { return t * t * t * t }
+
+// OutQuart decelerates to zero velocity.
+func OutQuart(t float64) float64 {
+	t--
+	return 1 - t*t*t*t
+}
+
+// InOutQuart accelerates until halfway, then decelerates.
+func InOutQuart(t float64) float64 {
+	if t < 0.5 {
+		return 8 * t * t * t * t
+	}
+	t--
+	return 1 - 8*t*t*t*t
+}
+
+// InSine accelerates following a sine curve.
+func InSine(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+
+// OutSine decelerates following a sine curve.
+func OutSine(t float64) float64 { return math.Sin(t * math.Pi / 2) }
+
+// InOutSine accelerates and decelerates following a sine curve.
+func InOutSine(t float64) float64 { return -(math.Cos(math.Pi*t) - 1) / 2 }
+
+// InExpo accelerates exponentially.
+func InExpo(t float64) float64 {
+	if t == 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// OutExpo decelerates exponentially.
+func OutExpo(t float64) float64 {
+	if t == 1 {
+		return 1
+	}
+	return 1 - math.Pow(2, -10*t)
+}
+
+// InOutExpo accelerates and decelerates exponentially.
+func InOutExpo(t float64) float64 {
+	switch {
+	case t == 0, t == 1:
+		return t
+	case t < 0.5:
+		return math.Pow(2, 20*t-10) / 2
+	}
+	return (2 - math.Pow(2, -20*t+10)) / 2
+}
+
+const backOvershoot = 1.70158
+
+// InBack pulls back slightly before moving forward.
+func InBack(t float64) float64 {
+	return (backOvershoot+1)*t*t*t - backOvershoot*t*t
+}
+
+// OutBack overshoots the target slightly before settling.
+func OutBack(t float64) float64 {
+	t--
+	return 1 + (backOvershoot+1)*t*t*t + backOvershoot*t*t
+}
+
+// InOutBack combines InBack and OutBack.
+func InOutBack(t float64) float64 {
+	const s = backOvershoot * 1.525
+	if t < 0.5 {
+		return (2 * t) * (2 * t) * ((s+1)*2*t - s) / 2
+	}
+	t = 2*t - 2
+	return (t*t*((s+1)*t+s) + 2) / 2
+}
+
+// InElastic oscillates with growing amplitude before reaching the target.
+func InElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*(2*math.Pi/3))
+}
+
+// OutElastic overshoots and oscillates around the target before settling.
+func OutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// InOutElastic combines InElastic and OutElastic.
+func InOutElastic(t float64) float64 {
+	const c = 2 * math.Pi / 4.5
+	switch {
+	case t == 0, t == 1:
+		return t
+	case t < 0.5:
+		return -(math.Pow(2, 20*t-10) * math.Sin((20*t-11.125)*c)) / 2
+	}
+	return math.Pow(2, -20*t+10)*math.Sin((20*t-11.125)*c)/2 + 1
+}
+
+// OutBounce bounces against the target like a dropped ball.
+func OutBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	}
+	t -= 2.625 / d
+	return n*t*t + 0.984375
+}
+
+// InBounce bounces against the start before moving to the target.
+func InBounce(t float64) float64 { return 1 - OutBounce(1-t) }
+
+// InOutBounce combines InBounce and OutBounce.
+func InOutBounce(t float64) float64 {
+	if t < 0.5 {
+		return (1 - OutBounce(1-2*t)) / 2
+	}
+	return (1 + OutBounce(2*t-1)) / 2
+}
+
+// Reverse returns the easing function mirrored in time, turning an In function into an Out function.
+func Reverse(f Func) Func {
+	return func(t float64) float64 { return 1 - f(1-t) }
+}
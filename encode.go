@@ -0,0 +1,57 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ErrEmptyCanvas is returned when reading the pixels of a canvas with no width or height.
+var ErrEmptyCanvas = errors.New("canvas: canvas is empty")
+
+// Image returns a copy of the whole backing store as an *image.NRGBA. It fails with
+// ErrEmptyCanvas for a 0×0 canvas, and with the browser's SecurityError if the canvas
+// is tainted by cross-origin images.
+func (c *Canvas) Image() (img *image.NRGBA, err error) {
+	w, h := c.Size()
+	if w == 0 || h == 0 {
+		return nil, ErrEmptyCanvas
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			jsErr, ok := e.(*js.Error)
+			if !ok {
+				panic(e)
+			}
+			img, err = nil, fmt.Errorf("canvas: reading pixels failed: %v", jsErr)
+		}
+	}()
+	return c.GetContext2D().GetImageData(0, 0, w, h).Image(), nil
+}
+
+// EncodePNG writes the contents of the canvas to w as PNG using image/png.
+// Unlike ToDataURL the output doesn't depend on the browser's encoder and no
+// base64 string is built.
+func (c *Canvas) EncodePNG(w io.Writer) error {
+	img, err := c.Image()
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// EncodeJPEG writes the contents of the canvas to w as JPEG using image/jpeg.
+// o may be nil for the default quality. JPEG has no alpha channel, so
+// transparent pixels come out with their color channels as stored.
+func (c *Canvas) EncodeJPEG(w io.Writer, o *jpeg.Options) error {
+	img, err := c.Image()
+	if err != nil {
+		return err
+	}
+	return jpeg.Encode(w, img, o)
+}
@@ -0,0 +1,34 @@
+package canvas
+
+import "math"
+
+// WithEraser runs fn with destination-out compositing, so that anything fn fills or
+// strokes erases the canvas to transparency instead of painting on it.
+// The previous state is restored afterwards.
+func (ctx *Context2D) WithEraser(fn func(ctx *Context2D)) {
+	ctx.Save()
+	defer ctx.Restore()
+	ctx.SetCompositeOp(CompositeDestinationOut)
+	// with destination-out only alpha matters; make sure a translucent style doesn't
+	// leave a half erased result
+	ctx.FillStyle = "#000"
+	ctx.StrokeStyle = "#000"
+	fn(ctx)
+}
+
+// EraseCircle clears a disc of radius r centred at (x, y) to transparency.
+func (ctx *Context2D) EraseCircle(x, y, r float64) {
+	ctx.WithEraser(func(ctx *Context2D) {
+		ctx.BeginPath()
+		ctx.Arc(x, y, r, 0, 2*math.Pi)
+		ctx.Fill()
+	})
+}
+
+// EraseRect clears a rectangle to transparency. Unlike ClearRect it honours the
+// global alpha, erasing only partially when it is below 1.
+func (ctx *Context2D) EraseRect(x, y, width, height float64) {
+	ctx.WithEraser(func(ctx *Context2D) {
+		ctx.FillRect(x, y, width, height)
+	})
+}
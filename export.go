@@ -0,0 +1,72 @@
+package canvas
+
+import (
+	"errors"
+	"image"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ErrEmptyRegion is returned when an export region doesn't overlap the canvas.
+var ErrEmptyRegion = errors.New("canvas: export region is empty")
+
+// ToDataURLRegion encodes the pixels of r, in backing store pixels, as a data URL of
+// the given mime type such as "image/png" or "image/jpeg". quality between 0 and 1
+// applies to lossy formats; 0 or less uses the browser default. r is clipped to the
+// canvas; an empty result gives "data:,".
+func (c *Canvas) ToDataURLRegion(r image.Rectangle, mime string, quality float64) string {
+	tmp := c.cropRegion(r)
+	if tmp == nil {
+		return "data:,"
+	}
+	return dataURL(tmp, mime, quality)
+}
+
+// ToBlobRegion encodes the pixels of r like ToDataURLRegion and calls done with the
+// encoded bytes once the browser has produced them.
+func (c *Canvas) ToBlobRegion(r image.Rectangle, mime string, quality float64, done func([]byte, error)) {
+	tmp := c.cropRegion(r)
+	if tmp == nil {
+		done(nil, ErrEmptyRegion)
+		return
+	}
+	cb := func(blob *js.Object) {
+		if blob == nil {
+			done(nil, errors.New("canvas: encoding "+mime+" failed"))
+			return
+		}
+		readBlob(blob, done)
+	}
+	if quality > 0 {
+		tmp.Call("toBlob", cb, mime, quality)
+	} else {
+		tmp.Call("toBlob", cb, mime)
+	}
+}
+
+// cropRegion copies the part of the canvas inside r into a new canvas, nil if r
+// doesn't overlap the canvas.
+func (c *Canvas) cropRegion(r image.Rectangle) *Canvas {
+	w, h := c.Size()
+	r = r.Intersect(image.Rect(0, 0, w, h))
+	if r.Empty() {
+		return nil
+	}
+	tmp := Create(r.Dx(), r.Dy())
+	x, y := float64(r.Min.X), float64(r.Min.Y)
+	dw, dh := float64(r.Dx()), float64(r.Dy())
+	tmp.GetContext2D().DrawImageRegion(c, x, y, dw, dh, 0, 0, dw, dh)
+	return tmp
+}
+
+// dataURL calls toDataURL on c with mime, "image/png" if empty, passing quality
+// only if it is positive.
+func dataURL(c *Canvas, mime string, quality float64) string {
+	if mime == "" {
+		mime = "image/png"
+	}
+	if quality > 0 {
+		return c.Call("toDataURL", mime, quality).String()
+	}
+	return c.Call("toDataURL", mime).String()
+}
@@ -0,0 +1,139 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// FastContext exposes the hottest drawing calls of a Context2D with less overhead:
+// the methods are looked up and bound once, when the FastContext is created, instead
+// of by name on every call. Use it for tight loops issuing many path or rectangle
+// calls; everything else is available through Context.
+//
+// The bound methods are the ones in place at creation, so create the FastContext
+// after EnableMetrics if its calls should be counted.
+type FastContext struct {
+	ctx *Context2D
+
+	moveTo, lineTo, bezierCurveTo, quadraticCurveTo *js.Object
+	arc, rect, beginPath, closePath, fill, stroke   *js.Object
+	fillRect, strokeRect, clearRect, drawImage      *js.Object
+	save, restore, translate, setTransform          *js.Object
+}
+
+// Fast returns a FastContext for ctx.
+func (ctx *Context2D) Fast() *FastContext {
+	bind := func(name string) *js.Object {
+		return ctx.Get(name).Call("bind", ctx.Object)
+	}
+	return &FastContext{
+		ctx:              ctx,
+		moveTo:           bind("moveTo"),
+		lineTo:           bind("lineTo"),
+		bezierCurveTo:    bind("bezierCurveTo"),
+		quadraticCurveTo: bind("quadraticCurveTo"),
+		arc:              bind("arc"),
+		rect:             bind("rect"),
+		beginPath:        bind("beginPath"),
+		closePath:        bind("closePath"),
+		fill:             bind("fill"),
+		stroke:           bind("stroke"),
+		fillRect:         bind("fillRect"),
+		strokeRect:       bind("strokeRect"),
+		clearRect:        bind("clearRect"),
+		drawImage:        bind("drawImage"),
+		save:             bind("save"),
+		restore:          bind("restore"),
+		translate:        bind("translate"),
+		setTransform:     bind("setTransform"),
+	}
+}
+
+// Context returns the wrapped context.
+func (fc *FastContext) Context() *Context2D { return fc.ctx }
+
+// MoveTo is Context2D.MoveTo.
+func (fc *FastContext) MoveTo(x, y float64) { fc.moveTo.Invoke(x, y) }
+
+// LineTo is Context2D.LineTo.
+func (fc *FastContext) LineTo(x, y float64) { fc.lineTo.Invoke(x, y) }
+
+// BezierCurveTo is Context2D.BezierCurveTo.
+func (fc *FastContext) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) {
+	fc.bezierCurveTo.Invoke(cp1x, cp1y, cp2x, cp2y, x, y)
+}
+
+// QuadraticCurveTo is Context2D.QuadraticCurveTo.
+func (fc *FastContext) QuadraticCurveTo(cpx, cpy, x, y float64) {
+	fc.quadraticCurveTo.Invoke(cpx, cpy, x, y)
+}
+
+// Arc is Context2D.Arc with an explicit direction.
+func (fc *FastContext) Arc(x, y, radius, sAngle, eAngle float64, counterclockwise bool) {
+	fc.arc.Invoke(x, y, radius, sAngle, eAngle, counterclockwise)
+}
+
+// Rect is Context2D.Rect.
+func (fc *FastContext) Rect(x, y, width, height float64) { fc.rect.Invoke(x, y, width, height) }
+
+// BeginPath is Context2D.BeginPath.
+func (fc *FastContext) BeginPath() { fc.beginPath.Invoke() }
+
+// ClosePath is Context2D.ClosePath.
+func (fc *FastContext) ClosePath() { fc.closePath.Invoke() }
+
+// Fill is Context2D.Fill.
+func (fc *FastContext) Fill() { fc.fill.Invoke() }
+
+// Stroke is Context2D.Stroke.
+func (fc *FastContext) Stroke() { fc.stroke.Invoke() }
+
+// FillRect is Context2D.FillRect.
+func (fc *FastContext) FillRect(x, y, width, height float64) {
+	fc.fillRect.Invoke(x, y, width, height)
+}
+
+// StrokeRect is Context2D.StrokeRect.
+func (fc *FastContext) StrokeRect(x, y, width, height float64) {
+	fc.strokeRect.Invoke(x, y, width, height)
+}
+
+// ClearRect is Context2D.ClearRect.
+func (fc *FastContext) ClearRect(x, y, width, height float64) {
+	fc.clearRect.Invoke(x, y, width, height)
+}
+
+// DrawImage is Context2D.DrawImage taking the image source object directly, as
+// returned by CanvasImageSource.ImageSource, to skip the interface call.
+func (fc *FastContext) DrawImage(src *js.Object, dx, dy, dw, dh float64) {
+	fc.drawImage.Invoke(src, dx, dy, dw, dh)
+}
+
+// Save is Context2D.Save.
+func (fc *FastContext) Save() {
+	fc.save.Invoke()
+	if fc.ctx.xf != nil {
+		fc.ctx.xf.save()
+	}
+}
+
+// Restore is Context2D.Restore.
+func (fc *FastContext) Restore() {
+	fc.restore.Invoke()
+	if fc.ctx.xf != nil {
+		fc.ctx.xf.restore()
+	}
+}
+
+// Translate is Context2D.Translate.
+func (fc *FastContext) Translate(x, y float64) {
+	fc.translate.Invoke(x, y)
+	if fc.ctx.xf != nil {
+		fc.ctx.xf.apply(Translation(x, y))
+	}
+}
+
+// SetTransform is Context2D.SetTransform.
+func (fc *FastContext) SetTransform(a, b, c, d, e, f float64) {
+	fc.setTransform.Invoke(a, b, c, d, e, f)
+	if fc.ctx.xf != nil {
+		fc.ctx.xf.set(Matrix{a, b, c, d, e, f})
+	}
+}
@@ -0,0 +1,142 @@
+package filters
+
+import (
+	"math"
+	"sort"
+
+	canvas "github.com/oskca/gopherjs-canvas"
+)
+
+// LUT is a lookup table mapping input channel values to output values.
+type LUT [256]uint8
+
+// IdentityLUT returns a LUT mapping every value to itself.
+func IdentityLUT() *LUT {
+	var l LUT
+	for i := range l {
+		l[i] = uint8(i)
+	}
+	return &l
+}
+
+// ApplyLUT maps the red, green and blue channels of img through lut. Alpha is left untouched.
+func ApplyLUT(img *canvas.ImageData, lut *LUT) {
+	ApplyLUTs(img, lut, lut, lut)
+}
+
+// ApplyLUTs maps each color channel of img through its own table. A nil table leaves the channel untouched.
+func ApplyLUTs(img *canvas.ImageData, r, g, b *LUT) {
+	pix := img.Bytes()
+	for o := 0; o+3 < len(pix); o += 4 {
+		if r != nil {
+			pix[o] = r[pix[o]]
+		}
+		if g != nil {
+			pix[o+1] = g[pix[o+1]]
+		}
+		if b != nil {
+			pix[o+2] = b[pix[o+2]]
+		}
+	}
+	img.SetBytes(pix)
+}
+
+// Threshold binarizes img: pixels whose luma is at least level become white, all others black.
+// Alpha is left untouched.
+func Threshold(img *canvas.ImageData, level uint8) {
+	pix := img.Bytes()
+	for o := 0; o+3 < len(pix); o += 4 {
+		var v uint8
+		if canvas.Luma(pix[o], pix[o+1], pix[o+2]) >= level {
+			v = 0xff
+		}
+		pix[o], pix[o+1], pix[o+2] = v, v, v
+	}
+	img.SetBytes(pix)
+}
+
+// Posterize reduces every color channel of img to the given number of evenly spaced levels.
+// levels below 2 are treated as 2.
+func Posterize(img *canvas.ImageData, levels int) {
+	ApplyLUT(img, PosterizeLUT(levels))
+}
+
+// PosterizeLUT returns the table used by Posterize.
+func PosterizeLUT(levels int) *LUT {
+	if levels < 2 {
+		levels = 2
+	}
+	var l LUT
+	step := 255 / float64(levels-1)
+	for i := range l {
+		l[i] = uint8(math.Round(math.Round(float64(i)/step) * step))
+	}
+	return &l
+}
+
+// Levels remaps the color channels of img: values up to inBlack become outBlack, values from
+// inWhite up become outWhite, and the range in between is stretched with the given gamma
+// (1 is linear, larger values brighten the midtones).
+func Levels(img *canvas.ImageData, inBlack, inWhite uint8, gamma float64, outBlack, outWhite uint8) {
+	ApplyLUT(img, LevelsLUT(inBlack, inWhite, gamma, outBlack, outWhite))
+}
+
+// LevelsLUT returns the table used by Levels.
+func LevelsLUT(inBlack, inWhite uint8, gamma float64, outBlack, outWhite uint8) *LUT {
+	if gamma <= 0 {
+		gamma = 1
+	}
+	var l LUT
+	span := float64(inWhite) - float64(inBlack)
+	for i := range l {
+		var t float64
+		switch {
+		case span <= 0:
+			if i >= int(inWhite) {
+				t = 1
+			}
+		default:
+			t = (float64(i) - float64(inBlack)) / span
+		}
+		t = math.Max(0, math.Min(1, t))
+		t = math.Pow(t, 1/gamma)
+		l[i] = uint8(math.Round(float64(outBlack) + t*(float64(outWhite)-float64(outBlack))))
+	}
+	return &l
+}
+
+// CurvePoint is a control point of a tone curve, mapping input value In to output value Out.
+type CurvePoint struct {
+	In, Out uint8
+}
+
+// CurveLUT returns a table interpolating linearly between the control points of a tone curve.
+// Values before the first or after the last point take that point's output.
+// Without points the identity table is returned.
+func CurveLUT(points []CurvePoint) *LUT {
+	if len(points) == 0 {
+		return IdentityLUT()
+	}
+	pts := append([]CurvePoint(nil), points...)
+	sort.Slice(pts, func(i, j int) bool { return pts[i].In < pts[j].In })
+	var l LUT
+	k := 0
+	for i := range l {
+		for k < len(pts)-1 && int(pts[k+1].In) <= i {
+			k++
+		}
+		p := pts[k]
+		if i <= int(pts[0].In) {
+			l[i] = pts[0].Out
+			continue
+		}
+		if k == len(pts)-1 {
+			l[i] = p.Out
+			continue
+		}
+		q := pts[k+1]
+		t := float64(i-int(p.In)) / float64(int(q.In)-int(p.In))
+		l[i] = uint8(math.Round(float64(p.Out) + t*(float64(q.Out)-float64(p.Out))))
+	}
+	return &l
+}
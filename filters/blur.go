@@ -0,0 +1,57 @@
+package filters
+
+import canvas "github.com/oskca/gopherjs-canvas"
+
+// StackBlur blurs img in place with a Gaussian-like blur of the given radius.
+// It runs three passes of a sliding window box blur, so the cost does not depend on radius.
+// Colors are blurred in premultiplied alpha to avoid dark fringes around transparent areas.
+func StackBlur(img *canvas.ImageData, radius int) {
+	if radius < 1 {
+		return
+	}
+	pix := img.Bytes()
+	BlurBytes(pix, img.Width, img.Height, radius)
+	img.SetBytes(pix)
+}
+
+// BlurBytes applies the blur of StackBlur to straight alpha RGBA pixel data in place.
+func BlurBytes(pix []byte, width, height, radius int) {
+	if radius < 1 || width == 0 || height == 0 {
+		return
+	}
+	canvas.PremultiplyBytes(pix)
+	tmp := make([]byte, len(pix))
+	for pass := 0; pass < 3; pass++ {
+		boxBlur(pix, tmp, width, height, radius, 4, 4*width)
+		boxBlur(tmp, pix, height, width, radius, 4*width, 4)
+	}
+	canvas.UnpremultiplyBytes(pix)
+}
+
+// boxBlur blurs lines of n pixels from src into dst. step is the byte distance between
+// neighbouring pixels of a line and stride the distance between lines; swapping them
+// turns a horizontal pass into a vertical one. Samples beyond the ends are clamped.
+func boxBlur(src, dst []byte, n, lines, radius, step, stride int) {
+	size := 2*radius + 1
+	for l := 0; l < lines; l++ {
+		base := l * stride
+		for c := 0; c < 4; c++ {
+			at := func(i int) int {
+				if i < 0 {
+					i = 0
+				} else if i >= n {
+					i = n - 1
+				}
+				return int(src[base+i*step+c])
+			}
+			sum := 0
+			for i := -radius; i <= radius; i++ {
+				sum += at(i)
+			}
+			for i := 0; i < n; i++ {
+				dst[base+i*step+c] = byte((sum + size/2) / size)
+				sum += at(i+radius+1) - at(i-radius)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Package filters implements pixel filters operating on canvas.ImageData.
+//
+// Filters read the pixels of an ImageData with a single bulk copy, process them in Go
+// and write the result back in one call, avoiding per-pixel JavaScript interop.
+package filters
+
+import (
+	canvas "github.com/oskca/gopherjs-canvas"
+	"github.com/oskca/gopherjs-canvas/filters/kernel"
+)
+
+// EdgeMode selects how pixels outside of the image are sampled.
+type EdgeMode = kernel.EdgeMode
+
+const (
+	// EdgeClamp repeats the nearest edge pixel.
+	EdgeClamp = kernel.EdgeClamp
+	// EdgeWrap samples from the opposite side of the image.
+	EdgeWrap = kernel.EdgeWrap
+	// EdgeTransparent treats pixels outside of the image as transparent black.
+	EdgeTransparent = kernel.EdgeTransparent
+)
+
+// ConvolveOptions controls Convolve. The zero value clamps edges and leaves the kernel as is.
+type ConvolveOptions = kernel.ConvolveOptions
+
+// Common kernels for use with Convolve.
+var (
+	Sharpen      = kernel.Sharpen
+	Emboss       = kernel.Emboss
+	EdgeDetect   = kernel.EdgeDetect
+	BoxBlur      = kernel.BoxBlur
+	GaussianBlur = kernel.GaussianBlur
+)
+
+// Convolve applies the convolution kernel to img in place.
+// The kernel is indexed as kernel[row][column] and centered on the pixel being computed;
+// rows must all have the same length. opts may be nil.
+func Convolve(img *canvas.ImageData, kernel [][]float64, opts *ConvolveOptions) {
+	pix := img.Bytes()
+	img.SetBytes(ConvolveBytes(pix, img.Width, img.Height, kernel, opts))
+}
+
+// ConvolveBytes applies the convolution kernel to RGBA pixel data of the given dimensions,
+// as returned by ImageData.Bytes, and returns the result in a new slice.
+// It is kernel.ConvolveBytes, which also builds outside of GopherJS.
+func ConvolveBytes(pix []byte, width, height int, k [][]float64, opts *ConvolveOptions) []byte {
+	return kernel.ConvolveBytes(pix, width, height, k, opts)
+}
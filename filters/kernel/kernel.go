@@ -0,0 +1,155 @@
+// Package kernel implements image convolution on raw RGBA bytes.
+//
+// It depends on nothing but the standard library, so unlike the rest of this module
+// it builds for any target, including GOOS=js GOARCH=wasm and native tests.
+// Package filters applies it to canvas.ImageData.
+package kernel
+
+import "math"
+
+// EdgeMode selects how pixels outside of the image are sampled.
+type EdgeMode int
+
+const (
+	// EdgeClamp repeats the nearest edge pixel.
+	EdgeClamp EdgeMode = iota
+	// EdgeWrap samples from the opposite side of the image.
+	EdgeWrap
+	// EdgeTransparent treats pixels outside of the image as transparent black.
+	EdgeTransparent
+)
+
+// ConvolveOptions controls ConvolveBytes. The zero value clamps edges and leaves the kernel as is.
+type ConvolveOptions struct {
+	Edge EdgeMode
+	// Normalize divides the result by the sum of the kernel weights when it is not zero.
+	Normalize bool
+	// Bias is added to each channel after weighting, e.g. 128 for emboss kernels.
+	Bias float64
+	// PreserveAlpha keeps the alpha channel of the source instead of convolving it.
+	PreserveAlpha bool
+}
+
+// Common kernels for use with ConvolveBytes.
+var (
+	Sharpen = [][]float64{
+		{0, -1, 0},
+		{-1, 5, -1},
+		{0, -1, 0},
+	}
+	Emboss = [][]float64{
+		{-2, -1, 0},
+		{-1, 1, 1},
+		{0, 1, 2},
+	}
+	EdgeDetect = [][]float64{
+		{-1, -1, -1},
+		{-1, 8, -1},
+		{-1, -1, -1},
+	}
+	BoxBlur = [][]float64{
+		{1, 1, 1},
+		{1, 1, 1},
+		{1, 1, 1},
+	}
+	GaussianBlur = [][]float64{
+		{1, 2, 1},
+		{2, 4, 2},
+		{1, 2, 1},
+	}
+)
+
+// ConvolveBytes applies the convolution kernel to RGBA pixel data of the given dimensions,
+// as returned by canvas.ImageData.Bytes, and returns the result in a new slice.
+func ConvolveBytes(pix []byte, width, height int, kernel [][]float64, opts *ConvolveOptions) []byte {
+	if opts == nil {
+		opts = &ConvolveOptions{}
+	}
+	kh := len(kernel)
+	if kh == 0 {
+		return append([]byte(nil), pix...)
+	}
+	kw := len(kernel[0])
+	cx, cy := kw/2, kh/2
+	scale := 1.0
+	if opts.Normalize {
+		var sum float64
+		for _, row := range kernel {
+			for _, k := range row {
+				sum += k
+			}
+		}
+		if sum != 0 {
+			scale = 1 / sum
+		}
+	}
+
+	out := make([]byte, len(pix))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b, a float64
+			for ky, row := range kernel {
+				sy, ok := edge(y+ky-cy, height, opts.Edge)
+				if !ok {
+					continue
+				}
+				for kx, k := range row {
+					if k == 0 {
+						continue
+					}
+					sx, ok := edge(x+kx-cx, width, opts.Edge)
+					if !ok {
+						continue
+					}
+					i := 4 * (sy*width + sx)
+					r += k * float64(pix[i])
+					g += k * float64(pix[i+1])
+					b += k * float64(pix[i+2])
+					a += k * float64(pix[i+3])
+				}
+			}
+			o := 4 * (y*width + x)
+			out[o] = clamp(r*scale + opts.Bias)
+			out[o+1] = clamp(g*scale + opts.Bias)
+			out[o+2] = clamp(b*scale + opts.Bias)
+			if opts.PreserveAlpha {
+				out[o+3] = pix[o+3]
+			} else {
+				out[o+3] = clamp(a * scale)
+			}
+		}
+	}
+	return out
+}
+
+// edge maps coordinate v into [0, n) according to mode.
+// ok is false when the sample lies outside of the image and should be skipped.
+func edge(v, n int, mode EdgeMode) (int, bool) {
+	if v >= 0 && v < n {
+		return v, true
+	}
+	switch mode {
+	case EdgeWrap:
+		v %= n
+		if v < 0 {
+			v += n
+		}
+		return v, true
+	case EdgeTransparent:
+		return 0, false
+	}
+	if v < 0 {
+		return 0, true
+	}
+	return n - 1, true
+}
+
+func clamp(v float64) byte {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return byte(math.Round(v))
+}
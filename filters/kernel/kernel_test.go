@@ -0,0 +1,100 @@
+package kernel
+
+import "testing"
+
+// testImage returns a 3x3 RGBA image whose red channel is r and alpha channel is a,
+// with green and blue left at 0. Pixels are in row order.
+func testImage(r, a [9]byte) []byte {
+	pix := make([]byte, 4*9)
+	for i := range r {
+		pix[4*i] = r[i]
+		pix[4*i+3] = a[i]
+	}
+	return pix
+}
+
+func TestConvolveBytes(t *testing.T) {
+	ramp := [9]byte{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 9,
+	}
+	opaque := [9]byte{255, 255, 255, 255, 255, 255, 255, 255, 255}
+	alphaRamp := [9]byte{10, 20, 30, 40, 50, 60, 70, 80, 90}
+	identity := [][]float64{{0, 0, 0}, {0, 1, 0}, {0, 0, 0}}
+	negated := [][]float64{{0, 0, 0}, {0, -1, 0}, {0, 0, 0}}
+	tests := []struct {
+		name   string
+		r, a   [9]byte
+		kernel [][]float64
+		opts   *ConvolveOptions
+		wantR  [9]byte
+		wantA  [9]byte
+	}{
+		{"nil options", ramp, opaque, identity, nil, ramp, opaque},
+		{"empty kernel copies", ramp, alphaRamp, nil, nil, ramp, alphaRamp},
+		// each sum covers the 3x3 neighbourhood with out of range rows and columns
+		// replaced by the nearest edge, e.g. (0,0) sums 1+1+2 + 1+1+2 + 4+4+5
+		{"box clamp", ramp, opaque, BoxBlur, &ConvolveOptions{Edge: EdgeClamp},
+			[9]byte{21, 27, 33, 39, 45, 51, 57, 63, 69}, opaque},
+		// every neighbourhood wraps around to cover the whole image once
+		{"box wrap", ramp, opaque, BoxBlur, &ConvolveOptions{Edge: EdgeWrap},
+			[9]byte{45, 45, 45, 45, 45, 45, 45, 45, 45}, opaque},
+		// only in range pixels count, e.g. (0,0) sums 1+2+4+5
+		{"box transparent", ramp, opaque, BoxBlur, &ConvolveOptions{Edge: EdgeTransparent},
+			[9]byte{12, 21, 16, 27, 45, 33, 24, 39, 28}, opaque},
+		// the clamp sums divided by 9: 21/9 rounds to 2, 33/9 to 4
+		{"box clamp normalized", ramp, alphaRamp, BoxBlur, &ConvolveOptions{Normalize: true},
+			[9]byte{2, 3, 4, 4, 5, 6, 6, 7, 8}, [9]byte{23, 30, 37, 43, 50, 57, 63, 70, 77}},
+		// missing pixels are transparent black, so corners fade: 4*255/9 and 6*255/9
+		{"box transparent normalized", ramp, opaque, BoxBlur, &ConvolveOptions{Edge: EdgeTransparent, Normalize: true},
+			[9]byte{1, 2, 2, 3, 5, 4, 3, 4, 3}, [9]byte{113, 170, 113, 170, 255, 170, 113, 170, 113}},
+		{"gaussian wrap normalized", ramp, opaque, GaussianBlur, &ConvolveOptions{Edge: EdgeWrap, Normalize: true},
+			// wrapped columns average to x offsets 0.75, 1, 1.25 and rows to 3 times that,
+			// e.g. (2,0): 1 + 1.25 + 2.25 = 4.5 rounds to 5
+			[9]byte{4, 4, 5, 5, 5, 5, 6, 6, 6}, opaque},
+		{"negative sum normalized", ramp, opaque, negated, &ConvolveOptions{Normalize: true}, ramp, opaque},
+		{"negative weights clamp to 0", ramp, opaque, negated, nil, [9]byte{}, [9]byte{}},
+		// the weights sum to 0, so Normalize leaves the kernel as is: 9v-45
+		{"zero sum normalized", ramp, opaque, EdgeDetect, &ConvolveOptions{Edge: EdgeWrap, Normalize: true},
+			[9]byte{0, 0, 0, 0, 0, 9, 18, 27, 36}, [9]byte{}},
+		// the same plus 128, e.g. (0,0): 9-45+128; alpha is not biased
+		{"bias", ramp, opaque, EdgeDetect, &ConvolveOptions{Edge: EdgeWrap, Bias: 128},
+			[9]byte{92, 101, 110, 119, 128, 137, 146, 155, 164}, [9]byte{}},
+		{"bias clamps", ramp, opaque, identity, &ConvolveOptions{Bias: 250},
+			[9]byte{251, 252, 253, 254, 255, 255, 255, 255, 255}, opaque},
+		{"preserve alpha", ramp, alphaRamp, BoxBlur, &ConvolveOptions{PreserveAlpha: true},
+			[9]byte{21, 27, 33, 39, 45, 51, 57, 63, 69}, alphaRamp},
+		{"preserve alpha with bias", ramp, alphaRamp, EdgeDetect, &ConvolveOptions{Edge: EdgeWrap, Bias: 128, PreserveAlpha: true},
+			[9]byte{92, 101, 110, 119, 128, 137, 146, 155, 164}, alphaRamp},
+	}
+	for _, tt := range tests {
+		src := testImage(tt.r, tt.a)
+		orig := append([]byte(nil), src...)
+		out := ConvolveBytes(src, 3, 3, tt.kernel, tt.opts)
+		if string(src) != string(orig) {
+			t.Errorf("%s: source modified", tt.name)
+		}
+		if len(out) != len(src) {
+			t.Errorf("%s: len = %d, want %d", tt.name, len(out), len(src))
+			continue
+		}
+		var bias float64
+		if tt.opts != nil && tt.kernel != nil {
+			bias = tt.opts.Bias
+		}
+		var gotR, gotA [9]byte
+		for i := range gotR {
+			gotR[i], gotA[i] = out[4*i], out[4*i+3]
+			if g, b := out[4*i+1], out[4*i+2]; g != clamp(bias) || b != clamp(bias) {
+				t.Errorf("%s: pixel %d green, blue = %d, %d, want %d", tt.name, i, g, b, clamp(bias))
+			}
+		}
+		if gotR != tt.wantR {
+			t.Errorf("%s: red = %v, want %v", tt.name, gotR, tt.wantR)
+		}
+		if gotA != tt.wantA {
+			t.Errorf("%s: alpha = %v, want %v", tt.name, gotA, tt.wantA)
+		}
+	}
+}
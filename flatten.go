@@ -0,0 +1,104 @@
+package canvas
+
+import "math"
+
+// maxFlattenSegments bounds the number of segments a single curve is split into,
+// guarding against tiny or zero tolerances.
+const maxFlattenSegments = 1 << 12
+
+// FlattenQuadratic approximates the quadratic Bézier curve from p0 to p2 with control point p1
+// by a polyline whose points never deviate more than tolerance from the curve.
+// The returned slice starts with p0 and ends with p2.
+func FlattenQuadratic(p0, p1, p2 Point, tolerance float64) []Point {
+	dd := math.Hypot(p0.X-2*p1.X+p2.X, p0.Y-2*p1.Y+p2.Y)
+	n := flattenSegments(math.Sqrt(dd / (4 * tolerance)))
+	pts := make([]Point, 0, n+1)
+	pts = append(pts, p0)
+	for i := 1; i < n; i++ {
+		t := float64(i) / float64(n)
+		mt := 1 - t
+		pts = append(pts, Point{
+			X: mt*mt*p0.X + 2*mt*t*p1.X + t*t*p2.X,
+			Y: mt*mt*p0.Y + 2*mt*t*p1.Y + t*t*p2.Y,
+		})
+	}
+	return append(pts, p2)
+}
+
+// FlattenCubic approximates the cubic Bézier curve from p0 to p3 with control points p1 and p2
+// by a polyline whose points never deviate more than tolerance from the curve.
+// The returned slice starts with p0 and ends with p3.
+func FlattenCubic(p0, p1, p2, p3 Point, tolerance float64) []Point {
+	dd := math.Max(
+		math.Hypot(p0.X-2*p1.X+p2.X, p0.Y-2*p1.Y+p2.Y),
+		math.Hypot(p1.X-2*p2.X+p3.X, p1.Y-2*p2.Y+p3.Y),
+	)
+	n := flattenSegments(math.Sqrt(3 * dd / (4 * tolerance)))
+	pts := make([]Point, 0, n+1)
+	pts = append(pts, p0)
+	for i := 1; i < n; i++ {
+		t := float64(i) / float64(n)
+		mt := 1 - t
+		a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+		pts = append(pts, Point{
+			X: a*p0.X + b*p1.X + c*p2.X + d*p3.X,
+			Y: a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y,
+		})
+	}
+	return append(pts, p3)
+}
+
+// FlattenArc approximates the arc drawn by ctx.arc(x, y, radius, startAngle, endAngle, anticlockwise)
+// by a polyline whose points never deviate more than tolerance from the arc.
+// Angles are in radians and are interpreted exactly like the Canvas 2D API does, so a sweep of
+// 2π or more yields a full circle.
+func FlattenArc(x, y, radius, startAngle, endAngle float64, anticlockwise bool, tolerance float64) []Point {
+	sweep := arcSweep(startAngle, endAngle, anticlockwise)
+	n := 1
+	if radius > tolerance {
+		step := 2 * math.Acos(1-tolerance/radius)
+		n = flattenSegments(math.Abs(sweep) / step)
+	}
+	pts := make([]Point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		a := startAngle + sweep*float64(i)/float64(n)
+		pts = append(pts, Point{X: x + radius*math.Cos(a), Y: y + radius*math.Sin(a)})
+	}
+	return pts
+}
+
+// arcSweep returns the signed angle covered by an arc, following the normalization rules of
+// CanvasRenderingContext2D.arc().
+func arcSweep(startAngle, endAngle float64, anticlockwise bool) float64 {
+	const twoPi = 2 * math.Pi
+	sweep := endAngle - startAngle
+	if anticlockwise {
+		if sweep <= -twoPi {
+			return -twoPi
+		}
+		sweep = math.Mod(sweep, twoPi)
+		if sweep > 0 {
+			sweep -= twoPi
+		}
+		return sweep
+	}
+	if sweep >= twoPi {
+		return twoPi
+	}
+	sweep = math.Mod(sweep, twoPi)
+	if sweep < 0 {
+		sweep += twoPi
+	}
+	return sweep
+}
+
+// flattenSegments turns an estimated segment count into a usable one.
+func flattenSegments(n float64) int {
+	if math.IsNaN(n) || n < 1 {
+		return 1
+	}
+	if n > maxFlattenSegments {
+		return maxFlattenSegments
+	}
+	return int(math.Ceil(n))
+}
@@ -0,0 +1,115 @@
+package canvas
+
+import (
+	"math"
+	"testing"
+)
+
+// distToPolyline returns the distance from p to the nearest segment of pts.
+func distToPolyline(p Point, pts []Point) float64 {
+	best := math.Inf(1)
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		d := b.Sub(a)
+		t := 0.0
+		if l := d.X*d.X + d.Y*d.Y; l > 0 {
+			t = math.Max(0, math.Min(1, ((p.X-a.X)*d.X+(p.Y-a.Y)*d.Y)/l))
+		}
+		q := a.Add(d.Mul(t))
+		best = math.Min(best, math.Hypot(p.X-q.X, p.Y-q.Y))
+	}
+	return best
+}
+
+func TestFlattenQuadraticTolerance(t *testing.T) {
+	p0, p1, p2 := Pt(0, 0), Pt(50, 100), Pt(100, 0)
+	for _, tol := range []float64{10, 1, 0.25, 0.01} {
+		pts := FlattenQuadratic(p0, p1, p2, tol)
+		if pts[0] != p0 || pts[len(pts)-1] != p2 {
+			t.Fatalf("tolerance %v: endpoints %v, %v", tol, pts[0], pts[len(pts)-1])
+		}
+		for i := 0; i <= 200; i++ {
+			s := float64(i) / 200
+			ms := 1 - s
+			c := Point{
+				X: ms*ms*p0.X + 2*ms*s*p1.X + s*s*p2.X,
+				Y: ms*ms*p0.Y + 2*ms*s*p1.Y + s*s*p2.Y,
+			}
+			if d := distToPolyline(c, pts); d > tol*1.0001 {
+				t.Fatalf("tolerance %v: curve point %v is %v away", tol, c, d)
+			}
+		}
+	}
+}
+
+func TestFlattenCubicTolerance(t *testing.T) {
+	p0, p1, p2, p3 := Pt(0, 0), Pt(0, 100), Pt(100, 100), Pt(100, 0)
+	for _, tol := range []float64{5, 0.5, 0.05} {
+		pts := FlattenCubic(p0, p1, p2, p3, tol)
+		for i := 0; i <= 200; i++ {
+			s := float64(i) / 200
+			ms := 1 - s
+			a, b, c, d := ms*ms*ms, 3*ms*ms*s, 3*ms*s*s, s*s*s
+			q := Point{
+				X: a*p0.X + b*p1.X + c*p2.X + d*p3.X,
+				Y: a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y,
+			}
+			if dist := distToPolyline(q, pts); dist > tol*1.0001 {
+				t.Fatalf("tolerance %v: curve point %v is %v away", tol, q, dist)
+			}
+		}
+	}
+}
+
+func TestFlattenSegmentCaps(t *testing.T) {
+	tests := []struct {
+		name string
+		pts  []Point
+		want int
+	}{
+		{"straight quadratic", FlattenQuadratic(Pt(0, 0), Pt(5, 5), Pt(10, 10), 0.1), 2},
+		{"zero tolerance quadratic", FlattenQuadratic(Pt(0, 0), Pt(50, 100), Pt(100, 0), 0), maxFlattenSegments + 1},
+		{"negative tolerance cubic", FlattenCubic(Pt(0, 0), Pt(0, 100), Pt(100, 100), Pt(100, 0), -1), 2},
+		{"NaN tolerance cubic", FlattenCubic(Pt(0, 0), Pt(0, 100), Pt(100, 100), Pt(100, 0), math.NaN()), 2},
+		{"radius below tolerance", FlattenArc(0, 0, 0.5, 0, math.Pi, false, 1), 2},
+		{"tiny tolerance arc", FlattenArc(0, 0, 1e6, 0, 2*math.Pi, false, 1e-9), maxFlattenSegments + 1},
+	}
+	for _, tt := range tests {
+		if got := len(tt.pts); got != tt.want {
+			t.Errorf("%s: got %d points, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestArcSweep(t *testing.T) {
+	tests := []struct {
+		start, end float64
+		ccw        bool
+		want       float64
+	}{
+		{0, math.Pi, false, math.Pi},
+		{0, math.Pi, true, -math.Pi},
+		{0, -math.Pi / 2, false, 3 * math.Pi / 2},
+		{0, 3 * math.Pi, false, 2 * math.Pi},
+		{0, -3 * math.Pi, true, -2 * math.Pi},
+		{1, 1, false, 0},
+	}
+	for _, tt := range tests {
+		if got := arcSweep(tt.start, tt.end, tt.ccw); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("arcSweep(%v, %v, %v) = %v, want %v", tt.start, tt.end, tt.ccw, got, tt.want)
+		}
+	}
+}
+
+func TestFlattenArcFullCircle(t *testing.T) {
+	pts := FlattenArc(10, 20, 5, 0, 2*math.Pi, false, 0.01)
+	first, last := pts[0], pts[len(pts)-1]
+	if math.Hypot(first.X-last.X, first.Y-last.Y) > 1e-9 {
+		t.Errorf("full circle does not close: %v != %v", first, last)
+	}
+	for _, p := range pts {
+		if r := math.Hypot(p.X-10, p.Y-20); math.Abs(r-5) > 1e-9 {
+			t.Fatalf("point %v has radius %v", p, r)
+		}
+	}
+}
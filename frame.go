@@ -0,0 +1,126 @@
+package canvas
+
+// FrameRenderer gives rendering an explicit frame structure: drawing between
+// BeginFrame and EndFrame goes into a command buffer and reaches the canvas in one
+// flush at EndFrame. The flush clears the canvas or just the dirty areas, limits
+// drawing to those areas, optionally goes through a back buffer, and closes the
+// frame of a Metrics.
+type FrameRenderer struct {
+	// AutoClear clears the canvas, or the dirty areas, before the flush.
+	AutoClear bool
+	// Background, if set, is painted instead of clearing to transparency.
+	Background string
+	// DoubleBuffer flushes into an offscreen canvas of the same size first and copies
+	// it to the canvas with a single drawImage, so a half drawn frame is never visible
+	// to readers of the canvas such as a MediaRecorder.
+	DoubleBuffer bool
+	// Metrics, if set, gets its EndFrame called after every flush.
+	Metrics *Metrics
+
+	ctx    *Context2D
+	rec    *RecordingContext
+	dirty  []Rect
+	active bool
+	back   *Canvas
+}
+
+// NewFrameRenderer creates a renderer flushing onto ctx and clearing it every frame.
+func NewFrameRenderer(ctx *Context2D) *FrameRenderer {
+	return &FrameRenderer{AutoClear: true, ctx: ctx, rec: NewRecordingContext(nil)}
+}
+
+// BeginFrame starts a frame and returns the context to draw it with.
+// The commands are only queued; nothing reaches the canvas before EndFrame.
+func (r *FrameRenderer) BeginFrame() *RecordingContext {
+	r.rec.Reset()
+	r.dirty = r.dirty[:0]
+	r.active = true
+	return r.rec
+}
+
+// Invalidate marks a rectangle, in the user space of the target context, as changed
+// in this frame. If any rectangle is marked, the flush only clears and draws inside
+// the marked rectangles; otherwise the whole canvas is redrawn.
+func (r *FrameRenderer) Invalidate(area Rect) {
+	r.dirty = append(r.dirty, area)
+}
+
+// Commands returns the commands queued for the current frame.
+func (r *FrameRenderer) Commands() CommandLog {
+	return r.rec.Log
+}
+
+// EndFrame flushes the queued commands onto the canvas and ends the frame.
+func (r *FrameRenderer) EndFrame() {
+	if !r.active {
+		return
+	}
+	r.active = false
+	dst := r.ctx
+	if r.DoubleBuffer {
+		dst = r.backBuffer()
+	}
+	r.flush(dst)
+	if r.DoubleBuffer {
+		c := r.ctx
+		w, h := c.Canvas().Size()
+		c.Save()
+		c.SetTransform(1, 0, 0, 1, 0, 0)
+		c.GlobalAlpha = 1
+		c.SetCompositeOp(CompositeCopy)
+		c.DrawImage(r.back, 0, 0, float64(w), float64(h))
+		c.Restore()
+	}
+	if r.Metrics != nil {
+		r.Metrics.EndFrame()
+	}
+}
+
+func (r *FrameRenderer) flush(ctx *Context2D) {
+	ctx.Save()
+	defer ctx.Restore()
+	if len(r.dirty) > 0 {
+		ctx.BeginPath()
+		for _, d := range r.dirty {
+			ctx.Rect(d.X, d.Y, d.W, d.H)
+		}
+		ctx.Clip()
+	}
+	if r.AutoClear {
+		r.clear(ctx)
+	}
+	r.rec.Log.Replay(ctx)
+}
+
+func (r *FrameRenderer) clear(ctx *Context2D) {
+	if len(r.dirty) == 0 {
+		if r.Background != "" {
+			ctx.ClearWithColor(r.Background)
+		} else {
+			ctx.Clear()
+		}
+		return
+	}
+	for _, d := range r.dirty {
+		ctx.ClearRect(d.X, d.Y, d.W, d.H)
+		if r.Background != "" {
+			ctx.FillStyle = r.Background
+			ctx.FillRect(d.X, d.Y, d.W, d.H)
+		}
+	}
+}
+
+// backBuffer returns the offscreen canvas matching the target, with the same transform.
+func (r *FrameRenderer) backBuffer() *Context2D {
+	w, h := r.ctx.Canvas().Size()
+	if r.back == nil {
+		r.back = Create(w, h)
+	} else if bw, bh := r.back.Size(); bw != w || bh != h {
+		r.back.Set("width", w)
+		r.back.Set("height", h)
+	}
+	ctx := r.back.GetContext2D()
+	ctx.SetMatrix(r.ctx.CurrentTransform())
+	// the back buffer keeps the previous frame, so partial redraws stay correct
+	return ctx
+}
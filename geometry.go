@@ -0,0 +1,114 @@
+package canvas
+
+import "math"
+
+// Point is a 2D point in canvas coordinate space.
+type Point struct {
+	X, Y float64
+}
+
+// Pt is shorthand for Point{x, y}.
+func Pt(x, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+// Add returns the vector p+q.
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the vector p-q.
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Mul returns the vector p*k.
+func (p Point) Mul(k float64) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+// Rect is an axis-aligned rectangle with its origin at the top-left corner.
+type Rect struct {
+	X, Y, W, H float64
+}
+
+// Contains reports whether p lies inside r. Points on the right and bottom edges are excluded.
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.X && p.X < r.X+r.W && p.Y >= r.Y && p.Y < r.Y+r.H
+}
+
+// Intersects reports whether r and s overlap.
+func (r Rect) Intersects(s Rect) bool {
+	return r.X < s.X+s.W && s.X < r.X+r.W && r.Y < s.Y+s.H && s.Y < r.Y+r.H
+}
+
+// Union returns the smallest rectangle containing both r and s.
+func (r Rect) Union(s Rect) Rect {
+	x0, y0 := math.Min(r.X, s.X), math.Min(r.Y, s.Y)
+	x1, y1 := math.Max(r.X+r.W, s.X+s.W), math.Max(r.Y+r.H, s.Y+s.H)
+	return Rect{x0, y0, x1 - x0, y1 - y0}
+}
+
+// Intersect returns the overlap of r and s, with zero size if they don't overlap.
+func (r Rect) Intersect(s Rect) Rect {
+	x0, y0 := math.Max(r.X, s.X), math.Max(r.Y, s.Y)
+	x1, y1 := math.Min(r.X+r.W, s.X+s.W), math.Min(r.Y+r.H, s.Y+s.H)
+	return Rect{x0, y0, math.Max(0, x1-x0), math.Max(0, y1-y0)}
+}
+
+// PointInPolygon reports whether p lies inside the closed polygon poly using the even-odd rule,
+// matching isPointInPath(x, y, "evenodd").
+func PointInPolygon(p Point, poly []Point) bool {
+	inside := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) &&
+			p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// SegmentIntersection returns the intersection point of the segments a0-a1 and b0-b1.
+// ok is false if the segments do not intersect or are parallel.
+func SegmentIntersection(a0, a1, b0, b1 Point) (p Point, ok bool) {
+	r := a1.Sub(a0)
+	s := b1.Sub(b0)
+	den := r.X*s.Y - r.Y*s.X
+	if den == 0 {
+		return Point{}, false
+	}
+	d := b0.Sub(a0)
+	t := (d.X*s.Y - d.Y*s.X) / den
+	u := (d.X*r.Y - d.Y*r.X) / den
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return Point{}, false
+	}
+	return a0.Add(r.Mul(t)), true
+}
+
+// PolygonArea returns the signed area of the closed polygon poly.
+// In canvas coordinates (y pointing down) the area is positive for clockwise polygons.
+func PolygonArea(poly []Point) float64 {
+	var sum float64
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		sum += poly[j].X*poly[i].Y - poly[i].X*poly[j].Y
+	}
+	return sum / 2
+}
+
+// BoundingBox returns the smallest Rect containing all of pts.
+// The zero Rect is returned for an empty slice.
+func BoundingBox(pts []Point) Rect {
+	if len(pts) == 0 {
+		return Rect{}
+	}
+	x0, y0 := pts[0].X, pts[0].Y
+	x1, y1 := x0, y0
+	for _, p := range pts[1:] {
+		x0, x1 = math.Min(x0, p.X), math.Max(x1, p.X)
+		y0, y1 = math.Min(y0, p.Y), math.Max(y1, p.Y)
+	}
+	return Rect{x0, y0, x1 - x0, y1 - y0}
+}
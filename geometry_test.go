@@ -0,0 +1,82 @@
+package canvas
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPointInPolygon(t *testing.T) {
+	square := []Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	// a concave "U" shape open at the top
+	u := []Point{{0, 0}, {3, 0}, {3, 7}, {7, 7}, {7, 0}, {10, 0}, {10, 10}, {0, 10}}
+	// a pentagram, whose center is outside under the even-odd rule
+	star := []Point{{5, 0}, {8, 10}, {0, 4}, {10, 4}, {2, 10}}
+	tests := []struct {
+		name string
+		p    Point
+		poly []Point
+		want bool
+	}{
+		{"inside square", Pt(5, 5), square, true},
+		{"outside square", Pt(15, 5), square, false},
+		{"left of square", Pt(-1, 5), square, false},
+		{"ray through vertex", Pt(-5, 0), square, false},
+		{"in notch of U", Pt(5, 3), u, false},
+		{"in arm of U", Pt(1, 3), u, true},
+		{"in base of U", Pt(5, 9), u, true},
+		{"star center evenodd", Pt(5, 5), star, false},
+		{"star tip", Pt(5, 2), star, true},
+		{"empty polygon", Pt(0, 0), nil, false},
+		{"single point", Pt(1, 1), []Point{{1, 1}}, false},
+		{"collinear polygon", Pt(5, 0), []Point{{0, 0}, {5, 0}, {10, 0}}, false},
+	}
+	for _, tt := range tests {
+		if got := PointInPolygon(tt.p, tt.poly); got != tt.want {
+			t.Errorf("%s: PointInPolygon(%v) = %v, want %v", tt.name, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestSegmentIntersection(t *testing.T) {
+	tests := []struct {
+		name           string
+		a0, a1, b0, b1 Point
+		want           Point
+		ok             bool
+	}{
+		{"crossing", Pt(0, 0), Pt(10, 10), Pt(0, 10), Pt(10, 0), Pt(5, 5), true},
+		{"touching at endpoint", Pt(0, 0), Pt(5, 5), Pt(5, 5), Pt(10, 0), Pt(5, 5), true},
+		{"T junction", Pt(0, 0), Pt(10, 0), Pt(5, -5), Pt(5, 0), Pt(5, 0), true},
+		{"lines cross beyond segments", Pt(0, 0), Pt(1, 1), Pt(0, 10), Pt(1, 9), Point{}, false},
+		{"parallel", Pt(0, 0), Pt(10, 0), Pt(0, 1), Pt(10, 1), Point{}, false},
+		{"collinear overlapping", Pt(0, 0), Pt(10, 0), Pt(5, 0), Pt(15, 0), Point{}, false},
+		{"degenerate segment", Pt(3, 3), Pt(3, 3), Pt(0, 0), Pt(10, 10), Point{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := SegmentIntersection(tt.a0, tt.a1, tt.b0, tt.b1)
+		if ok != tt.ok || math.Abs(got.X-tt.want.X) > 1e-9 || math.Abs(got.Y-tt.want.Y) > 1e-9 {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	tests := []struct {
+		name string
+		poly []Point
+		want float64
+	}{
+		{"clockwise square", []Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, 100},
+		{"counterclockwise square", []Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}}, -100},
+		{"triangle", []Point{{0, 0}, {4, 0}, {0, 3}}, 6},
+		{"collinear points", []Point{{0, 0}, {5, 5}, {10, 10}}, 0},
+		{"two points", []Point{{0, 0}, {5, 5}}, 0},
+		{"single point", []Point{{1, 1}}, 0},
+		{"empty", nil, 0},
+	}
+	for _, tt := range tests {
+		if got := PolygonArea(tt.poly); got != tt.want {
+			t.Errorf("%s: PolygonArea = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// GIFRecorder captures frames of a canvas at a fixed interval and encodes them
+// as an animated GIF in Go. Frames are quantized to Palette as they are captured.
+type GIFRecorder struct {
+	// Palette used to quantize frames, palette.Plan9 by default.
+	Palette color.Palette
+	// Dither enables Floyd-Steinberg dithering while quantizing. Enabled by default.
+	Dither bool
+	// LoopCount is the number of times the animation repeats; 0 loops forever, -1 plays once.
+	LoopCount int
+	// MaxFrames stops the capture once this many frames were recorded; 0 means no limit.
+	MaxFrames int
+
+	c        *Canvas
+	interval time.Duration
+	frames   []*image.Paletted
+	delays   []int
+	handle   *js.Object
+}
+
+// ErrNoFrames is returned by GIFRecorder.Encode when no frame has been captured.
+var ErrNoFrames = errors.New("canvas: no frames captured")
+
+// NewGIFRecorder creates a GIFRecorder capturing c every interval once started.
+func NewGIFRecorder(c *Canvas, interval time.Duration) *GIFRecorder {
+	return &GIFRecorder{
+		Palette:  palette.Plan9,
+		Dither:   true,
+		c:        c,
+		interval: interval,
+	}
+}
+
+// Start captures a frame immediately and then every interval until Stop is called.
+func (g *GIFRecorder) Start() {
+	if g.handle != nil {
+		return
+	}
+	g.Capture()
+	g.handle = js.Global.Call("setInterval", func() {
+		g.Capture()
+	}, float64(g.interval)/float64(time.Millisecond))
+}
+
+// Stop ends the periodic capture.
+func (g *GIFRecorder) Stop() {
+	if g.handle == nil {
+		return
+	}
+	js.Global.Call("clearInterval", g.handle)
+	g.handle = nil
+}
+
+// Capture records the current canvas contents as a frame shown for one interval.
+func (g *GIFRecorder) Capture() {
+	if g.MaxFrames > 0 && len(g.frames) >= g.MaxFrames {
+		g.Stop()
+		return
+	}
+	w, h := g.c.Get("width").Int(), g.c.Get("height").Int()
+	pix := g.c.GetContext2D().GetImageData(0, 0, w, h).Bytes()
+	src := &image.NRGBA{Pix: pix, Stride: 4 * w, Rect: image.Rect(0, 0, w, h)}
+	dst := image.NewPaletted(src.Rect, g.Palette)
+	if g.Dither {
+		draw.FloydSteinberg.Draw(dst, dst.Rect, src, image.Point{})
+	} else {
+		draw.Draw(dst, dst.Rect, src, image.Point{}, draw.Src)
+	}
+	g.frames = append(g.frames, dst)
+	g.delays = append(g.delays, int(g.interval/(10*time.Millisecond)))
+}
+
+// Len returns the number of captured frames.
+func (g *GIFRecorder) Len() int {
+	return len(g.frames)
+}
+
+// Reset discards all captured frames.
+func (g *GIFRecorder) Reset() {
+	g.frames = nil
+	g.delays = nil
+}
+
+// Encode returns the captured frames as an animated GIF.
+func (g *GIFRecorder) Encode() ([]byte, error) {
+	if len(g.frames) == 0 {
+		return nil, ErrNoFrames
+	}
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:     g.frames,
+		Delay:     g.delays,
+		LoopCount: g.LoopCount,
+	})
+	return buf.Bytes(), err
+}
@@ -0,0 +1,388 @@
+// Package gpufilter runs image filters as WebGL fragment shaders on a hidden canvas.
+//
+// A Filter holds a source image as a texture. Each filter call renders a pass into an offscreen
+// texture, so several filters can be chained before the result is drawn onto a 2D context with
+// DrawTo or read back into an ImageData with ReadInto:
+//
+//	f, err := gpufilter.New()
+//	...
+//	f.Load(c)
+//	f.Blur(4)
+//	f.ColorMatrix(gpufilter.Grayscale)
+//	f.DrawTo(ctx, 0, 0)
+package gpufilter
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+	canvas "github.com/oskca/gopherjs-canvas"
+)
+
+// WebGL constants used by the package.
+const (
+	glTexture2D              = 0x0DE1
+	glTexture0               = 0x84C0
+	glRGBA                   = 0x1908
+	glUnsignedByte           = 0x1401
+	glFloat                  = 0x1406
+	glTriangleStrip          = 0x0005
+	glArrayBuffer            = 0x8892
+	glStaticDraw             = 0x88E4
+	glFramebuffer            = 0x8D40
+	glColorAttachment0       = 0x8CE0
+	glColorBufferBit         = 0x4000
+	glTextureMinFilter       = 0x2801
+	glTextureMagFilter       = 0x2800
+	glTextureWrapS           = 0x2802
+	glTextureWrapT           = 0x2803
+	glLinear                 = 0x2601
+	glClampToEdge            = 0x812F
+	glVertexShader           = 0x8B31
+	glFragmentShader         = 0x8B30
+	glCompileStatus          = 0x8B81
+	glLinkStatus             = 0x8B82
+	glUnpackFlipY            = 0x9240
+	glUnpackPremultiplyAlpha = 0x9241
+)
+
+// maxBlurRadius is the largest number of taps on each side of the blur kernel.
+const maxBlurRadius = 32
+
+const vertexSource = `
+attribute vec2 p;
+varying vec2 uv;
+void main() {
+	uv = (p + 1.0) * 0.5;
+	gl_Position = vec4(p, 0.0, 1.0);
+}`
+
+const copySource = `
+precision mediump float;
+uniform sampler2D t;
+varying vec2 uv;
+void main() {
+	gl_FragColor = texture2D(t, uv);
+}`
+
+const blurSource = `
+precision mediump float;
+uniform sampler2D t;
+uniform vec2 dir;
+uniform float sigma;
+varying vec2 uv;
+void main() {
+	vec4 sum = vec4(0.0);
+	float total = 0.0;
+	for (int i = -32; i <= 32; i++) {
+		float x = float(i);
+		if (abs(x) > 3.0 * sigma) continue;
+		float w = exp(-x * x / (2.0 * sigma * sigma));
+		sum += texture2D(t, uv + dir * x) * w;
+		total += w;
+	}
+	gl_FragColor = sum / total;
+}`
+
+const colorMatrixSource = `
+precision mediump float;
+uniform sampler2D t;
+uniform float m[20];
+varying vec2 uv;
+void main() {
+	vec4 c = texture2D(t, uv);
+	if (c.a > 0.0) c.rgb /= c.a;
+	vec4 o = vec4(
+		m[0]*c.r + m[1]*c.g + m[2]*c.b + m[3]*c.a + m[4],
+		m[5]*c.r + m[6]*c.g + m[7]*c.b + m[8]*c.a + m[9],
+		m[10]*c.r + m[11]*c.g + m[12]*c.b + m[13]*c.a + m[14],
+		m[15]*c.r + m[16]*c.g + m[17]*c.b + m[18]*c.a + m[19]);
+	o = clamp(o, 0.0, 1.0);
+	gl_FragColor = vec4(o.rgb * o.a, o.a);
+}`
+
+const convolveSource = `
+precision mediump float;
+uniform sampler2D t;
+uniform float k[9];
+uniform vec2 px;
+varying vec2 uv;
+void main() {
+	vec3 sum = vec3(0.0);
+	for (int y = 0; y < 3; y++) {
+		for (int x = 0; x < 3; x++) {
+			sum += texture2D(t, uv + px * vec2(float(x - 1), float(1 - y))).rgb * k[y * 3 + x];
+		}
+	}
+	float a = texture2D(t, uv).a;
+	gl_FragColor = vec4(clamp(sum, 0.0, a), a);
+}`
+
+// Color matrices for ColorMatrix, in the row-major 4x5 layout of SVG feColorMatrix.
+var (
+	Identity = [20]float64{
+		1, 0, 0, 0, 0,
+		0, 1, 0, 0, 0,
+		0, 0, 1, 0, 0,
+		0, 0, 0, 1, 0,
+	}
+	Grayscale = [20]float64{
+		0.299, 0.587, 0.114, 0, 0,
+		0.299, 0.587, 0.114, 0, 0,
+		0.299, 0.587, 0.114, 0, 0,
+		0, 0, 0, 1, 0,
+	}
+	Sepia = [20]float64{
+		0.393, 0.769, 0.189, 0, 0,
+		0.349, 0.686, 0.168, 0, 0,
+		0.272, 0.534, 0.131, 0, 0,
+		0, 0, 0, 1, 0,
+	}
+	Invert = [20]float64{
+		-1, 0, 0, 0, 1,
+		0, -1, 0, 0, 1,
+		0, 0, -1, 0, 1,
+		0, 0, 0, 1, 0,
+	}
+)
+
+// ErrUnsupported is returned by New when WebGL is not available.
+var ErrUnsupported = errors.New("gpufilter: WebGL is not supported")
+
+// Filter applies shader based filters to an image loaded into it.
+type Filter struct {
+	canvas *canvas.Canvas
+	gl     *js.Object
+
+	copy, blur, colorMatrix, convolve *js.Object
+
+	source  *js.Object
+	targets [2]*js.Object
+	fbos    [2]*js.Object
+	current *js.Object // texture holding the latest result
+	next    int        // index of the target the next pass renders into
+	width   int
+	height  int
+}
+
+// New creates a Filter backed by a hidden WebGL canvas.
+func New() (*Filter, error) {
+	c := canvas.Create(1, 1)
+	gl := c.Call("getContext", "webgl", js.M{"premultipliedAlpha": true, "preserveDrawingBuffer": true})
+	if gl == nil {
+		return nil, ErrUnsupported
+	}
+	f := &Filter{canvas: c, gl: gl}
+	var err error
+	for _, p := range []struct {
+		dst *(*js.Object)
+		src string
+	}{
+		{&f.copy, copySource},
+		{&f.blur, blurSource},
+		{&f.colorMatrix, colorMatrixSource},
+		{&f.convolve, convolveSource},
+	} {
+		if *p.dst, err = f.program(p.src); err != nil {
+			return nil, err
+		}
+	}
+	buf := gl.Call("createBuffer")
+	gl.Call("bindBuffer", glArrayBuffer, buf)
+	gl.Call("bufferData", glArrayBuffer, js.Global.Get("Float32Array").New([]float32{-1, -1, 1, -1, -1, 1, 1, 1}), glStaticDraw)
+	f.source = f.texture()
+	for i := range f.targets {
+		f.targets[i] = f.texture()
+		f.fbos[i] = gl.Call("createFramebuffer")
+	}
+	return f, nil
+}
+
+// Canvas returns the hidden WebGL canvas. After DrawTo it holds the filtered image
+// and can be used as a canvas.CanvasImageSource.
+func (f *Filter) Canvas() *canvas.Canvas {
+	return f.canvas
+}
+
+// Load uploads src as the image to filter, discarding previous results.
+func (f *Filter) Load(src canvas.CanvasImageSource) {
+	w, h := canvas.SourceSize(src)
+	f.upload(src.ImageSource(), int(w), int(h))
+}
+
+// LoadImageData uploads img as the image to filter, discarding previous results.
+func (f *Filter) LoadImageData(img *canvas.ImageData) {
+	f.upload(img.Object, img.Width, img.Height)
+}
+
+func (f *Filter) upload(src *js.Object, w, h int) {
+	gl := f.gl
+	if w != f.width || h != f.height {
+		f.width, f.height = w, h
+		f.canvas.Set("width", w)
+		f.canvas.Set("height", h)
+		for i, t := range f.targets {
+			gl.Call("bindTexture", glTexture2D, t)
+			gl.Call("texImage2D", glTexture2D, 0, glRGBA, w, h, 0, glRGBA, glUnsignedByte, nil)
+			gl.Call("bindFramebuffer", glFramebuffer, f.fbos[i])
+			gl.Call("framebufferTexture2D", glFramebuffer, glColorAttachment0, glTexture2D, t, 0)
+		}
+		gl.Call("bindFramebuffer", glFramebuffer, nil)
+	}
+	gl.Call("bindTexture", glTexture2D, f.source)
+	gl.Call("pixelStorei", glUnpackFlipY, true)
+	gl.Call("pixelStorei", glUnpackPremultiplyAlpha, true)
+	gl.Call("texImage2D", glTexture2D, 0, glRGBA, glRGBA, glUnsignedByte, src)
+	gl.Call("pixelStorei", glUnpackFlipY, false)
+	gl.Call("pixelStorei", glUnpackPremultiplyAlpha, false)
+	f.current = f.source
+	f.next = 0
+}
+
+// Blur applies a Gaussian blur with the given radius in pixels, capped at 32.
+func (f *Filter) Blur(radius float64) {
+	if radius <= 0 {
+		return
+	}
+	if radius > maxBlurRadius {
+		radius = maxBlurRadius
+	}
+	sigma := radius / 3
+	if sigma < 0.5 {
+		sigma = 0.5
+	}
+	f.pass(f.blur, func(gl *js.Object) {
+		gl.Call("uniform2f", gl.Call("getUniformLocation", f.blur, "dir"), 1/float64(f.width), 0)
+		gl.Call("uniform1f", gl.Call("getUniformLocation", f.blur, "sigma"), sigma)
+	})
+	f.pass(f.blur, func(gl *js.Object) {
+		gl.Call("uniform2f", gl.Call("getUniformLocation", f.blur, "dir"), 0, 1/float64(f.height))
+		gl.Call("uniform1f", gl.Call("getUniformLocation", f.blur, "sigma"), sigma)
+	})
+}
+
+// ColorMatrix transforms every pixel with a 4x5 color matrix operating on straight alpha
+// values between 0 and 1, laid out like SVG feColorMatrix.
+func (f *Filter) ColorMatrix(m [20]float64) {
+	f.pass(f.colorMatrix, func(gl *js.Object) {
+		gl.Call("uniform1fv", gl.Call("getUniformLocation", f.colorMatrix, "m"), float32s(m[:]))
+	})
+}
+
+// Convolve applies a 3x3 convolution kernel given in row-major order to the color channels.
+func (f *Filter) Convolve(kernel [9]float64) {
+	f.pass(f.convolve, func(gl *js.Object) {
+		gl.Call("uniform1fv", gl.Call("getUniformLocation", f.convolve, "k"), float32s(kernel[:]))
+		gl.Call("uniform2f", gl.Call("getUniformLocation", f.convolve, "px"), 1/float64(f.width), 1/float64(f.height))
+	})
+}
+
+// DrawTo renders the current result onto the hidden canvas and draws it onto ctx at (x, y).
+func (f *Filter) DrawTo(ctx *canvas.Context2D, x, y float64) {
+	f.present()
+	ctx.DrawImage(f.canvas, x, y, float64(f.width), float64(f.height))
+}
+
+// ReadInto copies the current result into img, which must have the size of the loaded image.
+func (f *Filter) ReadInto(img *canvas.ImageData) {
+	gl := f.gl
+	f.present()
+	buf := make([]byte, 4*f.width*f.height)
+	arr := js.Global.Get("Uint8Array").New(len(buf))
+	gl.Call("readPixels", 0, 0, f.width, f.height, glRGBA, glUnsignedByte, arr)
+	raw := arr.Interface().([]byte)
+	stride := 4 * f.width
+	for y := 0; y < f.height; y++ {
+		copy(buf[y*stride:(y+1)*stride], raw[(f.height-1-y)*stride:(f.height-y)*stride])
+	}
+	canvas.UnpremultiplyBytes(buf)
+	img.SetBytes(buf)
+}
+
+// pass renders the current result through prog into the next offscreen target.
+func (f *Filter) pass(prog *js.Object, uniforms func(gl *js.Object)) {
+	if f.current == nil {
+		return
+	}
+	gl := f.gl
+	gl.Call("bindFramebuffer", glFramebuffer, f.fbos[f.next])
+	f.draw(prog, uniforms)
+	f.current = f.targets[f.next]
+	f.next = 1 - f.next
+}
+
+// present copies the current result to the canvas itself.
+func (f *Filter) present() {
+	if f.current == nil {
+		return
+	}
+	f.gl.Call("bindFramebuffer", glFramebuffer, nil)
+	f.draw(f.copy, nil)
+}
+
+func (f *Filter) draw(prog *js.Object, uniforms func(gl *js.Object)) {
+	gl := f.gl
+	gl.Call("viewport", 0, 0, f.width, f.height)
+	gl.Call("useProgram", prog)
+	gl.Call("activeTexture", glTexture0)
+	gl.Call("bindTexture", glTexture2D, f.current)
+	gl.Call("uniform1i", gl.Call("getUniformLocation", prog, "t"), 0)
+	loc := gl.Call("getAttribLocation", prog, "p")
+	gl.Call("enableVertexAttribArray", loc)
+	gl.Call("vertexAttribPointer", loc, 2, glFloat, false, 0, 0)
+	if uniforms != nil {
+		uniforms(gl)
+	}
+	gl.Call("clear", glColorBufferBit)
+	gl.Call("drawArrays", glTriangleStrip, 0, 4)
+}
+
+func (f *Filter) texture() *js.Object {
+	gl := f.gl
+	t := gl.Call("createTexture")
+	gl.Call("bindTexture", glTexture2D, t)
+	gl.Call("texParameteri", glTexture2D, glTextureMinFilter, glLinear)
+	gl.Call("texParameteri", glTexture2D, glTextureMagFilter, glLinear)
+	gl.Call("texParameteri", glTexture2D, glTextureWrapS, glClampToEdge)
+	gl.Call("texParameteri", glTexture2D, glTextureWrapT, glClampToEdge)
+	return t
+}
+
+func (f *Filter) program(fragment string) (*js.Object, error) {
+	gl := f.gl
+	vs, err := f.shader(glVertexShader, vertexSource)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := f.shader(glFragmentShader, fragment)
+	if err != nil {
+		return nil, err
+	}
+	p := gl.Call("createProgram")
+	gl.Call("attachShader", p, vs)
+	gl.Call("attachShader", p, fs)
+	gl.Call("linkProgram", p)
+	if !gl.Call("getProgramParameter", p, glLinkStatus).Bool() {
+		return nil, errors.New("gpufilter: linking program: " + gl.Call("getProgramInfoLog", p).String())
+	}
+	return p, nil
+}
+
+func (f *Filter) shader(typ int, src string) (*js.Object, error) {
+	gl := f.gl
+	s := gl.Call("createShader", typ)
+	gl.Call("shaderSource", s, src)
+	gl.Call("compileShader", s)
+	if !gl.Call("getShaderParameter", s, glCompileStatus).Bool() {
+		return nil, errors.New("gpufilter: compiling shader: " + gl.Call("getShaderInfoLog", s).String())
+	}
+	return s, nil
+}
+
+func float32s(v []float64) []float32 {
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(x)
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package canvas
+
+import (
+	"image/color"
+	"math"
+)
+
+// Stop is a color stop of a gradient.
+type Stop struct {
+	// Offset between 0 and 1.
+	Offset float64
+	Color  color.Color
+}
+
+// Stops adds all stops to the gradient and returns it.
+func (g *Gradient) Stops(stops []Stop) *Gradient {
+	for _, s := range stops {
+		g.AddColorStop(s.Offset, cssColor(s.Color))
+	}
+	return g
+}
+
+// NewLinearGradientAngle creates a linear gradient of the given length centered on (cx, cy),
+// running in the direction of angle (in radians, clockwise from the positive x axis),
+// with the given stops.
+func NewLinearGradientAngle(ctx *Context2D, cx, cy, length, angle float64, stops []Stop) *Gradient {
+	dx := math.Cos(angle) * length / 2
+	dy := math.Sin(angle) * length / 2
+	return ctx.CreateLinearGradient(cx-dx, cy-dy, cx+dx, cy+dy).Stops(stops)
+}
+
+// NewRadialGradientStops creates a radial gradient from the center (cx, cy) out to radius r
+// with the given stops.
+func NewRadialGradientStops(ctx *Context2D, cx, cy, r float64, stops []Stop) *Gradient {
+	return ctx.CreateRadialGradient(cx, cy, 0, cx, cy, r).Stops(stops)
+}
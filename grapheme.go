@@ -0,0 +1,126 @@
+package canvas
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+var graphemeSegmenter *js.Object
+
+// Graphemes splits s into grapheme clusters, the units a user perceives as single
+// characters: an emoji with skin tone or ZWJ sequence, a flag, or a letter with
+// combining marks each stay in one piece. Intl.Segmenter is used where available;
+// elsewhere a simplified set of the Unicode rules covering those cases applies.
+func Graphemes(s string) []string {
+	if s == "" {
+		return nil
+	}
+	if graphemeSegmenter == nil {
+		if intl := js.Global.Get("Intl"); intl != js.Undefined && intl.Get("Segmenter") != js.Undefined {
+			graphemeSegmenter = intl.Get("Segmenter").New(js.Undefined, js.M{"granularity": "grapheme"})
+		} else {
+			graphemeSegmenter = js.Undefined
+		}
+	}
+	if graphemeSegmenter == js.Undefined {
+		return splitGraphemes(s)
+	}
+	segs := js.Global.Get("Array").Call("from", graphemeSegmenter.Call("segment", s))
+	out := make([]string, segs.Length())
+	for i := range out {
+		out[i] = segs.Index(i).Get("segment").String()
+	}
+	return out
+}
+
+func splitGraphemes(s string) []string {
+	var out []string
+	start := 0
+	join, regional := false, false
+	for i, r := range s {
+		if i == 0 {
+			regional = isRegional(r)
+			continue
+		}
+		switch {
+		case join, isExtend(r):
+		case regional && isRegional(r):
+			// a flag is a pair of regional indicators
+			regional = false
+		default:
+			out = append(out, s[start:i])
+			start = i
+			regional = isRegional(r)
+		}
+		join = r == '\u200d'
+	}
+	return append(out, s[start:])
+}
+
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == '\u200d' ||
+		r >= 0xfe00 && r <= 0xfe0f || // variation selectors
+		r >= 0x1f3fb && r <= 0x1f3ff || // emoji skin tones
+		r >= 0xe0020 && r <= 0xe007f || // emoji tags
+		r >= 0xe0100 && r <= 0xe01ef
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// TruncateText shortens text with the current font so that it followed by ellipsis
+// fits into maxWidth, cutting only between grapheme clusters. Text that already
+// fits is returned unchanged; if not even the ellipsis fits, "" is returned.
+func (ctx *Context2D) TruncateText(text string, maxWidth float64, ellipsis string) string {
+	if ctx.MeasureText(text).Width <= maxWidth {
+		return text
+	}
+	g := Graphemes(text)
+	// binary search for the longest prefix that fits
+	lo, hi := 0, len(g)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if ctx.MeasureText(strings.Join(g[:mid], "")+ellipsis).Width <= maxWidth {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	if lo == 0 && ctx.MeasureText(ellipsis).Width > maxWidth {
+		return ""
+	}
+	return strings.TrimRight(strings.Join(g[:lo], ""), " ") + ellipsis
+}
+
+// CaretPositions returns the x offsets of every caret position in text drawn left
+// aligned at x 0 with the current font: one before each grapheme cluster and one
+// after the last, so the result has len(Graphemes(text))+1 entries.
+func (ctx *Context2D) CaretPositions(text string) []float64 {
+	g := Graphemes(text)
+	xs := make([]float64, len(g)+1)
+	prefix := ""
+	for i, c := range g {
+		prefix += c
+		xs[i+1] = ctx.MeasureText(prefix).Width
+	}
+	return xs
+}
+
+// CaretIndex returns the index of the grapheme cluster boundary of text closest to
+// x, e.g. to place a caret where the user clicked. x is relative to the start of
+// text drawn left aligned with the current font.
+func (ctx *Context2D) CaretIndex(text string, x float64) int {
+	xs := ctx.CaretPositions(text)
+	best := 0
+	for i := 1; i < len(xs); i++ {
+		if math.Abs(xs[i]-x) < math.Abs(xs[best]-x) {
+			best = i
+		}
+	}
+	return best
+}
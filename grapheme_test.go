@@ -0,0 +1,36 @@
+package canvas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitGraphemes(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"ascii", "abc", []string{"a", "b", "c"}},
+		{"single", "x", []string{"x"}},
+		{"combining accent", "e\u0301t\u0308", []string{"e\u0301", "t\u0308"}},
+		{"stacked marks", "a\u0301\u0323b", []string{"a\u0301\u0323", "b"}},
+		{"flag", "🇩🇪", []string{"🇩🇪"}},
+		{"adjacent flags", "🇩🇪🇫🇷🇯🇵", []string{"🇩🇪", "🇫🇷", "🇯🇵"}},
+		{"odd regional indicator", "🇩🇪🇫", []string{"🇩🇪", "🇫"}},
+		{"flag after text", "a🇩🇪b", []string{"a", "🇩🇪", "b"}},
+		{"tag flag", "🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f!", []string{"🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f", "!"}},
+		{"skin tone", "👍🏽", []string{"👍🏽"}},
+		{"skin tones in a row", "👋🏻👋🏿", []string{"👋🏻", "👋🏿"}},
+		{"zwj family", "👨\u200d👩\u200d👧\u200d👦", []string{"👨\u200d👩\u200d👧\u200d👦"}},
+		{"zwj with skin tones", "👩🏽\u200d💻x", []string{"👩🏽\u200d💻", "x"}},
+		{"zwj with variation selector", "🏳\ufe0f\u200d🌈", []string{"🏳\ufe0f\u200d🌈"}},
+		{"text presentation", "☺\ufe0e☺", []string{"☺\ufe0e", "☺"}},
+		{"leading mark", "\u0301a", []string{"\u0301", "a"}},
+	}
+	for _, tt := range tests {
+		if got := splitGraphemes(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: splitGraphemes(%+q) = %+q, want %+q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package canvas
+
+import "math"
+
+// GridStyle configures DrawGrid.
+type GridStyle struct {
+	// Color of the minor lines or dots.
+	Color string
+	// MajorColor of every MajorEvery-th line; empty to draw all lines in Color.
+	MajorColor string
+	// MajorEvery is the number of minor cells per major cell, 0 or 1 for no major lines.
+	MajorEvery int
+	// LineWidth in device pixels, 1 by default.
+	LineWidth float64
+	// Dots draws a dot at every intersection instead of lines.
+	Dots bool
+	// DotSize is the side of a dot in device pixels, 2 by default.
+	DotSize float64
+	// MinSpacing is the smallest distance between lines in device pixels. When zooming
+	// out makes the grid denser, the spacing is multiplied by MajorEvery (or doubled)
+	// until it is at least MinSpacing. 0 disables adaptation.
+	MinSpacing float64
+}
+
+// DrawGrid draws a grid with cells of spacing world units covering bounds, using the
+// current transform of ctx. Only the part on the canvas is drawn, all lines of a kind
+// go into a single path, and when the transform is axis-aligned lines are snapped
+// to device pixels so they stay crisp. Under rotation the grid is drawn unsnapped.
+func DrawGrid(ctx *Context2D, bounds Rect, spacing float64, style GridStyle) {
+	if spacing <= 0 || bounds.W <= 0 || bounds.H <= 0 {
+		return
+	}
+	m := ctx.CurrentTransform()
+	scale := math.Sqrt(math.Abs(m.Determinant()))
+	every := style.MajorEvery
+	if every < 1 {
+		every = 1
+	}
+	if style.MinSpacing > 0 {
+		grow := float64(every)
+		if grow < 2 {
+			grow = 2
+		}
+		for i := 0; spacing*scale < style.MinSpacing && i < 64; i++ {
+			spacing *= grow
+		}
+	}
+	lw := style.LineWidth
+	if lw <= 0 {
+		lw = 1
+	}
+
+	// cull bounds to the canvas
+	cw, ch := ctx.Canvas().Size()
+	if inv, ok := m.Invert(); ok {
+		visible := BoundingBox([]Point{
+			inv.TransformPoint(Point{}),
+			inv.TransformPoint(Point{X: float64(cw)}),
+			inv.TransformPoint(Point{X: float64(cw), Y: float64(ch)}),
+			inv.TransformPoint(Point{Y: float64(ch)}),
+		})
+		if !bounds.Intersects(visible) {
+			return
+		}
+		bounds = bounds.Intersect(visible)
+	}
+	i0 := int(math.Ceil(bounds.X / spacing))
+	i1 := int(math.Floor((bounds.X + bounds.W) / spacing))
+	j0 := int(math.Ceil(bounds.Y / spacing))
+	j1 := int(math.Floor((bounds.Y + bounds.H) / spacing))
+	isMajor := func(i int) bool {
+		return style.MajorColor != "" && every > 1 && i%every == 0
+	}
+
+	ctx.Save()
+	defer ctx.Restore()
+	axisAligned := m.B == 0 && m.C == 0
+	// map a world point to the space the path is built in
+	pt := func(x, y float64) Point { return Point{X: x, Y: y} }
+	if axisAligned {
+		ctx.SetTransform(1, 0, 0, 1, 0, 0)
+		pt = func(x, y float64) Point { return m.TransformPoint(Point{X: x, Y: y}) }
+	} else {
+		// keep the line width in device pixels
+		lw /= scale
+	}
+	snap := func(v float64) float64 {
+		if !axisAligned {
+			return v
+		}
+		if int(math.Round(lw))%2 == 1 {
+			return math.Floor(v) + 0.5
+		}
+		return math.Round(v)
+	}
+
+	if style.Dots {
+		size := style.DotSize
+		if size <= 0 {
+			size = 2
+		}
+		if !axisAligned {
+			size /= scale
+		}
+		for pass := 0; pass < 2; pass++ {
+			major := pass == 1
+			if major && style.MajorColor == "" {
+				break
+			}
+			ctx.BeginPath()
+			for j := j0; j <= j1; j++ {
+				for i := i0; i <= i1; i++ {
+					if (isMajor(i) && isMajor(j)) != major {
+						continue
+					}
+					p := pt(float64(i)*spacing, float64(j)*spacing)
+					x, y := p.X-size/2, p.Y-size/2
+					if axisAligned {
+						x, y = math.Round(x), math.Round(y)
+					}
+					ctx.Rect(x, y, size, size)
+				}
+			}
+			if major {
+				ctx.FillStyle = style.MajorColor
+			} else {
+				ctx.FillStyle = style.Color
+			}
+			ctx.Fill()
+		}
+		return
+	}
+
+	ctx.LineWidth = lw
+	ctx.SetLineCap(LineCapButt)
+	x0, x1 := bounds.X, bounds.X+bounds.W
+	y0, y1 := bounds.Y, bounds.Y+bounds.H
+	for pass := 0; pass < 2; pass++ {
+		major := pass == 1
+		if major && style.MajorColor == "" {
+			break
+		}
+		ctx.BeginPath()
+		for i := i0; i <= i1; i++ {
+			if isMajor(i) != major {
+				continue
+			}
+			x := float64(i) * spacing
+			a, b := pt(x, y0), pt(x, y1)
+			if axisAligned {
+				a.X, b.X = snap(a.X), snap(b.X)
+			}
+			ctx.MoveTo(a.X, a.Y)
+			ctx.LineTo(b.X, b.Y)
+		}
+		for j := j0; j <= j1; j++ {
+			if isMajor(j) != major {
+				continue
+			}
+			y := float64(j) * spacing
+			a, b := pt(x0, y), pt(x1, y)
+			if axisAligned {
+				a.Y, b.Y = snap(a.Y), snap(b.Y)
+			}
+			ctx.MoveTo(a.X, a.Y)
+			ctx.LineTo(b.X, b.Y)
+		}
+		if major {
+			ctx.StrokeStyle = style.MajorColor
+		} else {
+			ctx.StrokeStyle = style.Color
+		}
+		ctx.Stroke()
+	}
+}
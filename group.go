@@ -0,0 +1,79 @@
+package canvas
+
+// CanvasGroup draws the same content onto several contexts, each through its own
+// transform, e.g. an overview and a zoomed detail view kept in lockstep.
+type CanvasGroup struct {
+	// AutoClear clears every member before drawing.
+	AutoClear bool
+
+	members []groupMember
+}
+
+type groupMember struct {
+	ctx *Context2D
+	m   Matrix
+}
+
+// NewCanvasGroup creates an empty group.
+func NewCanvasGroup() *CanvasGroup {
+	return &CanvasGroup{}
+}
+
+// Add adds ctx to the group, drawing through view, which is multiplied with the
+// current transform of ctx.
+func (g *CanvasGroup) Add(ctx *Context2D, view Matrix) {
+	g.members = append(g.members, groupMember{ctx, view})
+}
+
+// SetView replaces the view transform of ctx.
+func (g *CanvasGroup) SetView(ctx *Context2D, view Matrix) {
+	for i := range g.members {
+		if g.members[i].ctx.Object == ctx.Object {
+			g.members[i].m = view
+		}
+	}
+}
+
+// Remove removes ctx from the group.
+func (g *CanvasGroup) Remove(ctx *Context2D) {
+	kept := g.members[:0]
+	for _, m := range g.members {
+		if m.ctx.Object != ctx.Object {
+			kept = append(kept, m)
+		}
+	}
+	g.members = kept
+}
+
+// Draw calls fn once per member with its context set up with the member's view.
+// The state of each context is saved before and restored after fn.
+func (g *CanvasGroup) Draw(fn func(ctx *Context2D)) {
+	for _, m := range g.members {
+		if g.AutoClear {
+			m.ctx.Clear()
+		}
+		m.ctx.Save()
+		m.ctx.TransformBy(m.m)
+		fn(m.ctx)
+		m.ctx.Restore()
+	}
+}
+
+// Replay replays log onto every member through its view.
+func (g *CanvasGroup) Replay(log CommandLog) {
+	g.Draw(log.Replay)
+}
+
+// DrawScene renders the nodes of s onto every member through its view.
+// Unlike Scene.Render the background is painted over the scene bounds only.
+func (g *CanvasGroup) DrawScene(s *Scene) {
+	g.Draw(func(ctx *Context2D) {
+		if s.Background != "" {
+			ctx.FillStyle = s.Background
+			ctx.FillRect(0, 0, s.Width, s.Height)
+		}
+		for _, n := range s.Nodes {
+			n.Draw(ctx)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package canvas
+
+import "math"
+
+// HatchKind selects the motif drawn by NewHatchPattern.
+type HatchKind int
+
+const (
+	// HatchDiagonal draws lines from bottom-left to top-right.
+	HatchDiagonal HatchKind = iota
+	// HatchBackDiagonal draws lines from top-left to bottom-right.
+	HatchBackDiagonal
+	// HatchCrosshatch draws both diagonals.
+	HatchCrosshatch
+	// HatchGrid draws horizontal and vertical lines.
+	HatchGrid
+	// HatchHorizontal draws horizontal stripes.
+	HatchHorizontal
+	// HatchVertical draws vertical stripes.
+	HatchVertical
+	// HatchDots draws a dot per tile; LineWidth is the dot diameter.
+	HatchDots
+)
+
+// Hatch describes a procedural pattern.
+type Hatch struct {
+	Kind HatchKind
+	// Spacing is the distance between lines or dots, 8 by default.
+	Spacing float64
+	// LineWidth is the width of lines or the diameter of dots, 1 by default.
+	LineWidth float64
+	// Color of lines and dots, black by default.
+	Color Style
+	// Background fills the tile before drawing; transparent if nil.
+	Background Style
+}
+
+// NewHatchPattern renders one tile of h to a small offscreen canvas and returns a repeating
+// pattern of it for ctx, usable as FillStyle or StrokeStyle through Pattern.Value.
+func NewHatchPattern(ctx *Context2D, h Hatch) *Pattern {
+	s := h.Spacing
+	if s <= 0 {
+		s = 8
+	}
+	lw := h.LineWidth
+	if lw <= 0 {
+		lw = 1
+	}
+	var color Style = "#000"
+	if h.Color != nil {
+		color = h.Color
+	}
+	size := int(math.Ceil(s))
+	tile := Create(size, size)
+	t := tile.GetContext2D()
+	fs := float64(size)
+	if h.Background != nil {
+		t.FillStyle = jsStyle(h.Background)
+		t.FillRect(0, 0, fs, fs)
+	}
+	t.StrokeStyle = jsStyle(color)
+	t.FillStyle = jsStyle(color)
+	t.LineWidth = lw
+	t.LineCap = LineCapSquare
+	t.BeginPath()
+	// Diagonals are drawn through the tile and its neighbours so they join seamlessly.
+	diagonal := func() {
+		for _, o := range []float64{-fs, 0, fs} {
+			t.MoveTo(o, fs)
+			t.LineTo(o+fs, 0)
+		}
+	}
+	backDiagonal := func() {
+		for _, o := range []float64{-fs, 0, fs} {
+			t.MoveTo(o, 0)
+			t.LineTo(o+fs, fs)
+		}
+	}
+	switch h.Kind {
+	case HatchDiagonal:
+		diagonal()
+	case HatchBackDiagonal:
+		backDiagonal()
+	case HatchCrosshatch:
+		diagonal()
+		backDiagonal()
+	case HatchGrid:
+		t.MoveTo(0, fs/2)
+		t.LineTo(fs, fs/2)
+		t.MoveTo(fs/2, 0)
+		t.LineTo(fs/2, fs)
+	case HatchHorizontal:
+		t.MoveTo(0, fs/2)
+		t.LineTo(fs, fs/2)
+	case HatchVertical:
+		t.MoveTo(fs/2, 0)
+		t.LineTo(fs/2, fs)
+	case HatchDots:
+		t.Arc(fs/2, fs/2, lw/2, 0, 2*math.Pi)
+		t.Fill()
+		return ctx.CreatePattern(tile, PatternRepeat)
+	}
+	t.Stroke()
+	return ctx.CreatePattern(tile, PatternRepeat)
+}
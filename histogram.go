@@ -0,0 +1,31 @@
+package canvas
+
+// Histogram counts the pixels per value of each channel, read with a single bulk copy.
+func (i *ImageData) Histogram() (r, g, b, a [256]int) {
+	pix := i.Bytes()
+	for o := 0; o+3 < len(pix); o += 4 {
+		r[pix[o]]++
+		g[pix[o+1]]++
+		b[pix[o+2]]++
+		a[pix[o+3]]++
+	}
+	return
+}
+
+// LuminanceHistogram counts the pixels per luma value, computed with the Rec. 601
+// weights 0.299, 0.587 and 0.114. Fully transparent pixels are skipped.
+func (i *ImageData) LuminanceHistogram() (l [256]int) {
+	pix := i.Bytes()
+	for o := 0; o+3 < len(pix); o += 4 {
+		if pix[o+3] == 0 {
+			continue
+		}
+		l[Luma(pix[o], pix[o+1], pix[o+2])]++
+	}
+	return
+}
+
+// Luma returns the Rec. 601 luma of an RGB color.
+func Luma(r, g, b uint8) uint8 {
+	return uint8((299*uint32(r) + 587*uint32(g) + 114*uint32(b) + 500) / 1000)
+}
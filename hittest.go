@@ -0,0 +1,167 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Shape is a region of the canvas that can be hit tested.
+type Shape interface {
+	// Contains reports whether p lies inside the shape.
+	Contains(p Point) bool
+	// Bounds returns the axis-aligned bounding box of the shape.
+	// A Rect with zero width or height means the bounds are unknown.
+	Bounds() Rect
+}
+
+// Bounds returns r itself, making Rect a Shape.
+func (r Rect) Bounds() Rect {
+	return r
+}
+
+// Circle is a circular Shape centered at (X, Y).
+type Circle struct {
+	X, Y, R float64
+}
+
+// Contains reports whether p lies inside the circle.
+func (c Circle) Contains(p Point) bool {
+	return math.Hypot(p.X-c.X, p.Y-c.Y) <= c.R
+}
+
+// Bounds returns the bounding box of the circle.
+func (c Circle) Bounds() Rect {
+	return Rect{c.X - c.R, c.Y - c.R, 2 * c.R, 2 * c.R}
+}
+
+// Polygon is a closed polygonal Shape, filled with the even-odd rule.
+type Polygon []Point
+
+// Contains reports whether p lies inside the polygon.
+func (poly Polygon) Contains(p Point) bool {
+	return PointInPolygon(p, poly)
+}
+
+// Bounds returns the bounding box of the polygon.
+func (poly Polygon) Bounds() Rect {
+	return BoundingBox(poly)
+}
+
+// PathShape is a Shape backed by a Path2D. Containment is answered by the browser
+// through Ctx.isPointInPath, so it reflects the transform currently set on Ctx.
+type PathShape struct {
+	Path *Path2D
+	Ctx  *Context2D
+	// Box optionally bounds the path so queries outside of it skip the browser call.
+	Box Rect
+}
+
+// Contains reports whether p lies inside the path.
+func (s PathShape) Contains(p Point) bool {
+	return s.Ctx.IsPointInPath2D(s.Path, p.X, p.Y)
+}
+
+// Bounds returns s.Box.
+func (s PathShape) Bounds() Rect {
+	return s.Box
+}
+
+// HitTester keeps a list of shapes identified by ID and answers
+// "what's under (x, y)?" queries. Shapes added later are considered to be on top.
+type HitTester struct {
+	ids    []string
+	shapes []Shape
+}
+
+// NewHitTester creates an empty HitTester.
+func NewHitTester() *HitTester {
+	return &HitTester{}
+}
+
+// Add registers s under id on top of all other shapes.
+// If id is already registered its shape is replaced and keeps its stacking position.
+func (h *HitTester) Add(id string, s Shape) {
+	if i := h.index(id); i >= 0 {
+		h.shapes[i] = s
+		return
+	}
+	h.ids = append(h.ids, id)
+	h.shapes = append(h.shapes, s)
+}
+
+// Remove unregisters the shape with the given id.
+func (h *HitTester) Remove(id string) {
+	if i := h.index(id); i >= 0 {
+		h.ids = append(h.ids[:i], h.ids[i+1:]...)
+		h.shapes = append(h.shapes[:i], h.shapes[i+1:]...)
+	}
+}
+
+// Clear unregisters all shapes.
+func (h *HitTester) Clear() {
+	h.ids = h.ids[:0]
+	h.shapes = h.shapes[:0]
+}
+
+// Len returns the number of registered shapes.
+func (h *HitTester) Len() int {
+	return len(h.ids)
+}
+
+// HitTest returns the id of the topmost shape containing (x, y).
+func (h *HitTester) HitTest(x, y float64) (id string, ok bool) {
+	p := Point{x, y}
+	for i := len(h.shapes) - 1; i >= 0; i-- {
+		if hitShape(h.shapes[i], p) {
+			return h.ids[i], true
+		}
+	}
+	return "", false
+}
+
+// HitTestAll returns the ids of all shapes containing (x, y), topmost first.
+func (h *HitTester) HitTestAll(x, y float64) []string {
+	p := Point{x, y}
+	var ids []string
+	for i := len(h.shapes) - 1; i >= 0; i-- {
+		if hitShape(h.shapes[i], p) {
+			ids = append(ids, h.ids[i])
+		}
+	}
+	return ids
+}
+
+// HitEvent hit tests the position of a pointer or mouse event on c.
+func (h *HitTester) HitEvent(c *Canvas, ev *js.Object) (id string, ok bool) {
+	p := c.EventPoint(ev)
+	return h.HitTest(p.X, p.Y)
+}
+
+// Attach listens for the event typ on c and calls fn with the id of the topmost
+// shape under the pointer, or "" if there is none. The returned function removes the listener.
+func (h *HitTester) Attach(c *Canvas, typ string, fn func(id string, p Point, ev *js.Object)) (remove func()) {
+	return c.OnPointer(typ, func(p Point, ev *js.Object) {
+		id, _ := h.HitTest(p.X, p.Y)
+		fn(id, p, ev)
+	})
+}
+
+func (h *HitTester) index(id string) int {
+	for i, v := range h.ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// hitShape checks the cheap bounding box first when it is known.
+func hitShape(s Shape, p Point) bool {
+	if b := s.Bounds(); b.W > 0 && b.H > 0 {
+		if p.X < b.X || p.X > b.X+b.W || p.Y < b.Y || p.Y > b.Y+b.H {
+			return false
+		}
+	}
+	return s.Contains(p)
+}
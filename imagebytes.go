@@ -0,0 +1,24 @@
+package canvas
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/oskca/gopherjs-dom"
+)
+
+// ImageFromBytes decodes encoded image data, such as PNG or JPEG bytes received over
+// a WebSocket, into an <img> element. mime is the type of data, e.g. "image/png".
+// onLoad is called asynchronously with the loaded element, which can be drawn
+// through ElementImage, or with an error if the browser can't decode the data.
+// The temporary object URL is revoked once loading finished.
+func ImageFromBytes(data []byte, mime string, onLoad func(*dom.Element, error)) {
+	blob := js.Global.Get("Blob").New([]interface{}{data}, js.M{"type": mime})
+	loadBlobImage(blob, func(img *js.Object, ok bool) {
+		if !ok {
+			onLoad(nil, errors.New("canvas: decoding "+mime+" image failed"))
+			return
+		}
+		onLoad(dom.WrapElement(img), nil)
+	})
+}
@@ -0,0 +1,127 @@
+package canvas
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ImageCache loads images by URL once and memoizes them, along with the patterns created from them.
+type ImageCache struct {
+	// CrossOrigin is assigned to the crossOrigin attribute of loaded images, e.g. "anonymous",
+	// so that drawing them does not taint the canvas.
+	CrossOrigin string
+	// OnLoad, if set, is called whenever an image finished loading, typically to trigger a redraw
+	// of a scene that used GetPattern or Image before the image was available.
+	OnLoad func(url string)
+
+	images   map[string]*cachedImage
+	patterns map[patternKey]*Pattern
+}
+
+type cachedImage struct {
+	img     JSImage
+	loaded  bool
+	err     error
+	waiters []func(JSImage, error)
+}
+
+type patternKey struct {
+	ctx *Context2D
+	url string
+	rep Repetition
+}
+
+// DefaultImageCache is the cache used by Context2D.GetPattern.
+var DefaultImageCache = NewImageCache()
+
+// NewImageCache creates an empty cache.
+func NewImageCache() *ImageCache {
+	return &ImageCache{
+		images:   map[string]*cachedImage{},
+		patterns: map[patternKey]*Pattern{},
+	}
+}
+
+// Load calls fn with the image at url once it is loaded, immediately if it already is.
+// The image is only requested once no matter how often Load is called.
+func (c *ImageCache) Load(url string, fn func(img JSImage, err error)) {
+	e := c.entry(url)
+	if e.loaded || e.err != nil {
+		fn(e.img, e.err)
+		return
+	}
+	e.waiters = append(e.waiters, fn)
+}
+
+// Image returns the image at url if it has finished loading, starting the load otherwise.
+func (c *ImageCache) Image(url string) (img JSImage, ok bool) {
+	e := c.entry(url)
+	return e.img, e.loaded
+}
+
+// Pattern returns the pattern of the image at url for ctx, creating it on first use.
+// It returns nil until the image has loaded; the load is started by the first call.
+func (c *ImageCache) Pattern(ctx *Context2D, url string, rep Repetition) *Pattern {
+	key := patternKey{ctx, url, rep}
+	if p, ok := c.patterns[key]; ok {
+		return p
+	}
+	img, ok := c.Image(url)
+	if !ok {
+		return nil
+	}
+	p := ctx.CreatePattern(img, rep)
+	c.patterns[key] = p
+	return p
+}
+
+// Forget removes url and the patterns created from it from the cache.
+func (c *ImageCache) Forget(url string) {
+	delete(c.images, url)
+	for k := range c.patterns {
+		if k.url == url {
+			delete(c.patterns, k)
+		}
+	}
+}
+
+func (c *ImageCache) entry(url string) *cachedImage {
+	if e, ok := c.images[url]; ok {
+		return e
+	}
+	img := js.Global.Get("Image").New()
+	e := &cachedImage{img: JSImage{img}}
+	c.images[url] = e
+	img.Set("onload", func() {
+		e.loaded = true
+		c.notify(url, e)
+	})
+	img.Set("onerror", func() {
+		e.err = errors.New("canvas: loading image " + url + " failed")
+		c.notify(url, e)
+	})
+	if c.CrossOrigin != "" {
+		img.Set("crossOrigin", c.CrossOrigin)
+	}
+	img.Set("src", url)
+	return e
+}
+
+func (c *ImageCache) notify(url string, e *cachedImage) {
+	waiters := e.waiters
+	e.waiters = nil
+	for _, fn := range waiters {
+		fn(e.img, e.err)
+	}
+	if e.err == nil && c.OnLoad != nil {
+		c.OnLoad(url)
+	}
+}
+
+// GetPattern returns a pattern of the image at url using DefaultImageCache.
+// It returns nil until the image has loaded, so callers redraw from DefaultImageCache.OnLoad
+// or fall back to a plain color in the meantime.
+func (ctx *Context2D) GetPattern(url string, rep Repetition) *Pattern {
+	return DefaultImageCache.Pattern(ctx, url, rep)
+}
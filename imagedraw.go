@@ -0,0 +1,87 @@
+package canvas
+
+// Common anchors for ImageTransform.Anchor, as fractions of the image size.
+var (
+	AnchorTopLeft     = Point{0, 0}
+	AnchorTop         = Point{0.5, 0}
+	AnchorTopRight    = Point{1, 0}
+	AnchorLeft        = Point{0, 0.5}
+	AnchorCenter      = Point{0.5, 0.5}
+	AnchorRight       = Point{1, 0.5}
+	AnchorBottomLeft  = Point{0, 1}
+	AnchorBottom      = Point{0.5, 1}
+	AnchorBottomRight = Point{1, 1}
+)
+
+// ImageTransform describes how DrawImageTransformed places an image.
+type ImageTransform struct {
+	// Width and Height of the drawn image before scaling; 0 uses the natural size.
+	Width, Height float64
+	// Anchor is the point of the image, as fractions of its size, that is placed
+	// at the drawing position and that rotation and scaling happen around.
+	// The zero value is the top left corner.
+	Anchor Point
+	// Rotation in radians, clockwise.
+	Rotation float64
+	// ScaleX and ScaleY scale the image around the anchor; 0 means 1.
+	ScaleX, ScaleY float64
+	// Alpha multiplies the global alpha when not 0.
+	Alpha float64
+}
+
+// DrawImageTransformed draws img with its anchor at x, y, rotated and scaled around
+// the anchor as described by t. The state of ctx is left unchanged.
+func (ctx *Context2D) DrawImageTransformed(img CanvasImageSource, x, y float64, t ImageTransform) {
+	w, h := t.Width, t.Height
+	if w == 0 || h == 0 {
+		nw, nh := SourceSize(img)
+		if w == 0 {
+			w = nw
+		}
+		if h == 0 {
+			h = nh
+		}
+	}
+	sx, sy := t.ScaleX, t.ScaleY
+	if sx == 0 {
+		sx = 1
+	}
+	if sy == 0 {
+		sy = 1
+	}
+	ctx.Save()
+	ctx.Translate(x, y)
+	if t.Rotation != 0 {
+		ctx.Rotate(t.Rotation)
+	}
+	if sx != 1 || sy != 1 {
+		ctx.Scale(sx, sy)
+	}
+	if t.Alpha != 0 {
+		ctx.GlobalAlpha *= t.Alpha
+	}
+	ctx.DrawImage(img, -t.Anchor.X*w, -t.Anchor.Y*h, w, h)
+	ctx.Restore()
+}
+
+// DrawImageFlipped draws img into the rectangle dx, dy, dw, dh like DrawImage,
+// mirrored horizontally if flipX and vertically if flipY. The image still covers
+// the same rectangle; only its content is mirrored. The state of ctx is left unchanged.
+func (ctx *Context2D) DrawImageFlipped(img CanvasImageSource, dx, dy, dw, dh float64, flipX, flipY bool) {
+	if !flipX && !flipY {
+		ctx.DrawImage(img, dx, dy, dw, dh)
+		return
+	}
+	sx, sy := 1.0, 1.0
+	if flipX {
+		sx, dx = -1, dx+dw
+	}
+	if flipY {
+		sy, dy = -1, dy+dh
+	}
+	ctx.Save()
+	ctx.Translate(dx, dy)
+	ctx.Scale(sx, sy)
+	ctx.DrawImage(img, 0, 0, dw, dh)
+	ctx.Restore()
+}
@@ -0,0 +1,64 @@
+package canvas
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/oskca/gopherjs-dom"
+)
+
+// CanvasImageSource is anything that can be drawn with DrawImage or repeated with CreatePattern:
+// a Canvas, an ImageBitmap or a wrapped <img>, <video> or <canvas> element.
+type CanvasImageSource interface {
+	// ImageSource returns the JavaScript object passed to drawImage.
+	ImageSource() *js.Object
+}
+
+// ImageSource returns the canvas element, so one canvas can be drawn onto another.
+func (c *Canvas) ImageSource() *js.Object {
+	return c.Object
+}
+
+// JSImage adapts a JavaScript image source such as an HTMLImageElement,
+// HTMLVideoElement or ImageBitmap to CanvasImageSource.
+type JSImage struct {
+	*js.Object
+}
+
+// ImageSource returns the wrapped object.
+func (i JSImage) ImageSource() *js.Object {
+	return i.Object
+}
+
+// ElementImage adapts an <img>, <video> or <canvas> element to CanvasImageSource.
+func ElementImage(el *dom.Element) JSImage {
+	return JSImage{el.Object}
+}
+
+// ImageBitmap The ImageBitmap interface represents a bitmap image which can be drawn to a <canvas> without undue latency.
+type ImageBitmap struct {
+	*js.Object
+	Width  int `js:"width"`
+	Height int `js:"height"`
+}
+
+// ImageSource returns the ImageBitmap object.
+func (b *ImageBitmap) ImageSource() *js.Object {
+	return b.Object
+}
+
+// Close Disposes of all graphical resources associated with the ImageBitmap.
+func (b *ImageBitmap) Close() {
+	b.Call("close")
+}
+
+// SourceSize returns the intrinsic size of an image source: naturalWidth/naturalHeight for images,
+// videoWidth/videoHeight for videos and width/height for everything else.
+func SourceSize(src CanvasImageSource) (width, height float64) {
+	o := src.ImageSource()
+	if w := o.Get("naturalWidth"); w != js.Undefined {
+		return w.Float(), o.Get("naturalHeight").Float()
+	}
+	if w := o.Get("videoWidth"); w != js.Undefined {
+		return w.Float(), o.Get("videoHeight").Float()
+	}
+	return o.Get("width").Float(), o.Get("height").Float()
+}
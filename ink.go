@@ -0,0 +1,183 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// InkPoint is a sample of a freehand stroke.
+type InkPoint struct {
+	Point
+	// Pressure between 0 and 1; 0.5 for devices that don't report it.
+	Pressure float64
+	// Time is the event timestamp in milliseconds.
+	Time float64
+}
+
+// InkCapture records freehand strokes from the pointer events of a canvas and draws
+// them as they are made. Every coalesced event is used where the browser supports
+// getCoalescedEvents, points closer than MinDistance are dropped, the rest are smoothed
+// and only the newest piece of the stroke is drawn on each event to keep latency low.
+type InkCapture struct {
+	// MinDistance is the minimum distance between kept points.
+	MinDistance float64
+	// Smoothing between 0 (none) and 1 (heavy) damps jitter in the input.
+	Smoothing float64
+	// Width of the stroke at full pressure.
+	Width float64
+	// Style of the stroke; anything accepted by Chain.StrokeWith.
+	Style Style
+	// PressureWidth makes the stroke width follow the pointer pressure.
+	PressureWidth bool
+	// OnStroke, if set, receives the smoothed points of every finished stroke.
+	OnStroke func(points []InkPoint)
+
+	canvas  *Canvas
+	ctx     *Context2D
+	stroke  []InkPoint
+	pointer int
+	active  bool
+	remove  func()
+}
+
+// NewInkCapture starts capturing strokes drawn on c.
+// Call Detach to stop listening.
+func NewInkCapture(c *Canvas) *InkCapture {
+	ink := &InkCapture{
+		MinDistance:   1,
+		Smoothing:     0.5,
+		Width:         3,
+		Style:         "black",
+		PressureWidth: true,
+		canvas:        c,
+		ctx:           c.GetContext2D(),
+	}
+	// keep touch input from scrolling the page instead of inking
+	c.Get("style").Set("touchAction", "none")
+	ink.remove = c.onPointerDrag(ink.down, ink.move, ink.up)
+	return ink
+}
+
+// Detach removes the event listeners. A stroke in progress is dropped.
+func (ink *InkCapture) Detach() {
+	ink.remove()
+	ink.active = false
+	ink.stroke = nil
+}
+
+// Stroke returns the points of the stroke in progress, nil if there is none.
+func (ink *InkCapture) Stroke() []InkPoint {
+	if !ink.active {
+		return nil
+	}
+	return ink.stroke
+}
+
+func (ink *InkCapture) down(p Point, ev *js.Object) {
+	if ink.active {
+		return
+	}
+	ink.active = true
+	ink.pointer = ev.Get("pointerId").Int()
+	ink.canvas.Call("setPointerCapture", ev.Get("pointerId"))
+	ink.stroke = ink.stroke[:0]
+	ink.stroke = append(ink.stroke, inkSample(p, ev))
+	ink.drawDot(ink.stroke[0])
+}
+
+func (ink *InkCapture) move(p Point, ev *js.Object) {
+	if !ink.active || ev.Get("pointerId").Int() != ink.pointer {
+		return
+	}
+	if ev.Get("getCoalescedEvents") != js.Undefined {
+		events := ev.Call("getCoalescedEvents")
+		if n := events.Length(); n > 0 {
+			for i := 0; i < n; i++ {
+				e := events.Index(i)
+				ink.add(inkSample(ink.canvas.EventPoint(e), e))
+			}
+			return
+		}
+	}
+	ink.add(inkSample(p, ev))
+}
+
+func (ink *InkCapture) up(p Point, ev *js.Object) {
+	if !ink.active || ev.Get("pointerId").Int() != ink.pointer {
+		return
+	}
+	ink.active = false
+	if n := len(ink.stroke); n > 1 {
+		// finish the last half segment left open by the midpoint scheme
+		a, b := ink.stroke[n-2], ink.stroke[n-1]
+		ink.segment(mid(a.Point, b.Point), b.Point, b.Point, b.Pressure)
+	}
+	if ink.OnStroke != nil {
+		pts := make([]InkPoint, len(ink.stroke))
+		copy(pts, ink.stroke)
+		ink.OnStroke(pts)
+	}
+}
+
+// add smooths and appends a sample, then draws the newly settled piece of the curve:
+// a quadratic from the midpoint of the previous pair through the previous point to
+// the midpoint of the newest pair.
+func (ink *InkCapture) add(s InkPoint) {
+	n := len(ink.stroke)
+	last := ink.stroke[n-1]
+	if math.Hypot(s.X-last.X, s.Y-last.Y) < ink.MinDistance {
+		return
+	}
+	k := math.Max(0, math.Min(0.95, ink.Smoothing))
+	s.Point = Point{X: last.X*k + s.X*(1-k), Y: last.Y*k + s.Y*(1-k)}
+	ink.stroke = append(ink.stroke, s)
+	from := last.Point
+	if n > 1 {
+		from = mid(ink.stroke[n-2].Point, last.Point)
+	}
+	ink.segment(from, last.Point, mid(last.Point, s.Point), (last.Pressure+s.Pressure)/2)
+}
+
+func (ink *InkCapture) segment(from, ctrl, to Point, pressure float64) {
+	ctx := ink.ctx
+	ctx.Save()
+	ctx.StrokeStyle = jsStyle(ink.Style)
+	ctx.LineWidth = ink.widthAt(pressure)
+	ctx.SetLineCap(LineCapRound)
+	ctx.SetLineJoin(LineJoinRound)
+	ctx.BeginPath()
+	ctx.MoveTo(from.X, from.Y)
+	ctx.QuadraticCurveTo(ctrl.X, ctrl.Y, to.X, to.Y)
+	ctx.Stroke()
+	ctx.Restore()
+}
+
+func (ink *InkCapture) drawDot(s InkPoint) {
+	ctx := ink.ctx
+	ctx.Save()
+	ctx.FillStyle = jsStyle(ink.Style)
+	ctx.BeginPath()
+	ctx.Arc(s.X, s.Y, ink.widthAt(s.Pressure)/2, 0, 2*math.Pi)
+	ctx.Fill()
+	ctx.Restore()
+}
+
+func (ink *InkCapture) widthAt(pressure float64) float64 {
+	if !ink.PressureWidth {
+		return ink.Width
+	}
+	return math.Max(ink.Width*pressure*2, 0.5)
+}
+
+func inkSample(p Point, ev *js.Object) InkPoint {
+	s := InkPoint{Point: p, Pressure: 0.5, Time: ev.Get("timeStamp").Float()}
+	if pr := ev.Get("pressure"); pr != js.Undefined && ev.Get("pointerType").String() != "mouse" {
+		s.Pressure = pr.Float()
+	}
+	return s
+}
+
+func mid(a, b Point) Point {
+	return Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
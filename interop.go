@@ -0,0 +1,29 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// Underlier is implemented by the element types of honnef.co/go/js/dom, such as
+// *dom.HTMLCanvasElement, and by any other wrapper exposing its JavaScript object.
+type Underlier interface {
+	Underlying() *js.Object
+}
+
+// NewFromDom creates a Canvas from a honnef.co/go/js/dom element without depending on that package:
+//
+//	el := dom.GetWindow().Document().GetElementByID("c").(*dom.HTMLCanvasElement)
+//	c := canvas.NewFromDom(el)
+func NewFromDom(el Underlier, opts ...Option) *Canvas {
+	return New(el.Underlying(), opts...)
+}
+
+// Underlying returns the HTMLCanvasElement, making Canvas an Underlier.
+// It converts back into a honnef.co/go/js/dom type with
+// dom.WrapHTMLElement(c.Underlying()).(*dom.HTMLCanvasElement).
+func (c *Canvas) Underlying() *js.Object {
+	return c.Object
+}
+
+// Underlying returns the CanvasRenderingContext2D object.
+func (ctx *Context2D) Underlying() *js.Object {
+	return ctx.Object
+}
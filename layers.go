@@ -0,0 +1,180 @@
+package canvas
+
+import (
+	"strconv"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// LayerStack manages canvases stacked on top of each other inside a container
+// element, e.g. a static background, the content and an overlay for UI. The layers
+// always cover the container: they are resized together when its size or the device
+// pixel ratio changes, and each keeps a HiDPI context drawing in CSS pixels.
+//
+// Splitting a scene this way lets rarely changing layers be drawn once while only
+// the dynamic ones are redrawn every frame.
+type LayerStack struct {
+	// OnResize is called after the layers have been resized, which clears them.
+	OnResize func(s *LayerStack)
+
+	container *js.Object
+	names     []string
+	layers    map[string]*Canvas
+	width     int
+	height    int
+	observer  *js.Object
+	dprQuery  *js.Object
+	dprChange *js.Object
+}
+
+// NewLayerStack creates one canvas per name inside container, bottom layer first.
+// The container is made a positioning context if it isn't one already.
+func NewLayerStack(container *js.Object, names ...string) *LayerStack {
+	s := &LayerStack{container: container, layers: map[string]*Canvas{}}
+	style := js.Global.Call("getComputedStyle", container)
+	if style.Get("position").String() == "static" {
+		container.Get("style").Set("position", "relative")
+	}
+	s.width, s.height = container.Get("clientWidth").Int(), container.Get("clientHeight").Int()
+	for _, name := range names {
+		s.Add(name)
+	}
+	s.observe()
+	return s
+}
+
+// Add appends a layer on top of the stack and returns it. Adding an existing name
+// returns the existing layer.
+func (s *LayerStack) Add(name string) *Canvas {
+	if c, ok := s.layers[name]; ok {
+		return c
+	}
+	el := js.Global.Get("document").Call("createElement", "canvas")
+	st := el.Get("style")
+	st.Set("position", "absolute")
+	st.Set("left", "0")
+	st.Set("top", "0")
+	st.Set("zIndex", strconv.Itoa(len(s.names)))
+	el.Get("dataset").Set("layer", name)
+	// only the top layer should receive pointer events
+	if len(s.names) > 0 {
+		s.layers[s.names[len(s.names)-1]].Get("style").Set("pointerEvents", "none")
+	}
+	s.container.Call("appendChild", el)
+	c := New(el, WithHiDPI(), WithSize(s.width, s.height))
+	s.layers[name] = c
+	s.names = append(s.names, name)
+	return c
+}
+
+// Layer returns the canvas of the named layer, nil if there is none.
+func (s *LayerStack) Layer(name string) *Canvas {
+	return s.layers[name]
+}
+
+// Context returns the 2D context of the named layer, nil if there is none.
+func (s *LayerStack) Context(name string) *Context2D {
+	c := s.layers[name]
+	if c == nil {
+		return nil
+	}
+	return c.GetContext2D()
+}
+
+// Names returns the layer names, bottom layer first.
+func (s *LayerStack) Names() []string {
+	return append([]string(nil), s.names...)
+}
+
+// SetVisible shows or hides the named layer.
+func (s *LayerStack) SetVisible(name string, visible bool) {
+	if c := s.layers[name]; c != nil {
+		v := ""
+		if !visible {
+			v = "hidden"
+		}
+		c.Get("style").Set("visibility", v)
+	}
+}
+
+// Size returns the size of the layers in CSS pixels.
+func (s *LayerStack) Size() (width, height int) {
+	return s.width, s.height
+}
+
+// Resize sets the size of every layer in CSS pixels, clearing them, and calls OnResize.
+func (s *LayerStack) Resize(width, height int) {
+	s.width, s.height = width, height
+	ratio := devicePixelRatio()
+	for _, name := range s.names {
+		c := s.layers[name]
+		c.pixelRatio = ratio
+		c.SetSize(width, height)
+	}
+	if s.OnResize != nil {
+		s.OnResize(s)
+	}
+}
+
+// Close stops tracking the container size and the pixel ratio, and removes the
+// layers from the container.
+func (s *LayerStack) Close() {
+	if s.observer != nil {
+		s.observer.Call("disconnect")
+		s.observer = nil
+	}
+	s.unwatchRatio()
+	for _, name := range s.names {
+		el := s.layers[name].Object
+		if p := el.Get("parentNode"); p != nil {
+			p.Call("removeChild", el)
+		}
+	}
+	s.names, s.layers = nil, map[string]*Canvas{}
+}
+
+func (s *LayerStack) observe() {
+	if ctor := js.Global.Get("ResizeObserver"); ctor != js.Undefined {
+		s.observer = ctor.New(func(entries *js.Object) {
+			w, h := s.container.Get("clientWidth").Int(), s.container.Get("clientHeight").Int()
+			if w == 0 || h == 0 || w == s.width && h == s.height {
+				return
+			}
+			s.Resize(w, h)
+		})
+		s.observer.Call("observe", s.container)
+	}
+	s.watchRatio()
+}
+
+// watchRatio listens for a change of devicePixelRatio, e.g. when the window moves to
+// another screen or the page is zoomed. A resolution media query only matches the
+// current ratio, so it is replaced after every change.
+func (s *LayerStack) watchRatio() {
+	if js.Global.Get("matchMedia") == js.Undefined {
+		return
+	}
+	q := "(resolution: " + strconv.FormatFloat(devicePixelRatio(), 'f', -1, 64) + "dppx)"
+	s.dprQuery = js.Global.Call("matchMedia", q)
+	s.dprChange = js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		s.unwatchRatio()
+		s.Resize(s.width, s.height)
+		s.watchRatio()
+		return nil
+	})
+	s.dprQuery.Call("addEventListener", "change", s.dprChange)
+}
+
+func (s *LayerStack) unwatchRatio() {
+	if s.dprQuery != nil {
+		s.dprQuery.Call("removeEventListener", "change", s.dprChange)
+		s.dprQuery, s.dprChange = nil, nil
+	}
+}
+
+func devicePixelRatio() float64 {
+	if r := js.Global.Get("devicePixelRatio"); r != js.Undefined && r.Float() > 0 {
+		return r.Float()
+	}
+	return 1
+}
@@ -0,0 +1,69 @@
+package canvas
+
+import "math"
+
+// DrawLine strokes a straight line from (x1, y1) to (x2, y2) with the current stroke style.
+func (ctx *Context2D) DrawLine(x1, y1, x2, y2 float64) {
+	ctx.BeginPath()
+	ctx.MoveTo(x1, y1)
+	ctx.LineTo(x2, y2)
+	ctx.Stroke()
+}
+
+// StrokePolyline strokes the open polyline through pts with the current stroke style.
+func (ctx *Context2D) StrokePolyline(pts []Point) {
+	if len(pts) < 2 {
+		return
+	}
+	ctx.BeginPath()
+	tracePolyline(ctx, pts)
+	ctx.Stroke()
+}
+
+// DrawLineCrisp is DrawLine with the end points snapped to the device pixel grid,
+// so thin horizontal and vertical lines cover whole pixels instead of being smeared
+// over two. See SnapPoint.
+func (ctx *Context2D) DrawLineCrisp(x1, y1, x2, y2 float64) {
+	snap := ctx.pixelSnapper()
+	a, b := snap(Point{X: x1, Y: y1}), snap(Point{X: x2, Y: y2})
+	ctx.DrawLine(a.X, a.Y, b.X, b.Y)
+}
+
+// StrokePolylineCrisp is StrokePolyline with every vertex snapped to the device
+// pixel grid. See SnapPoint.
+func (ctx *Context2D) StrokePolylineCrisp(pts []Point) {
+	snap := ctx.pixelSnapper()
+	snapped := make([]Point, len(pts))
+	for i, p := range pts {
+		snapped[i] = snap(p)
+	}
+	ctx.StrokePolyline(snapped)
+}
+
+// SnapPoint moves p, given in user space, so that a line of the current LineWidth
+// through it lies on whole device pixels: onto a pixel centre for odd widths and
+// onto a pixel edge for even ones. It uses CurrentTransform, so it is cheapest with
+// TrackTransform enabled. Under a rotated or skewed transform p is returned unchanged.
+func (ctx *Context2D) SnapPoint(p Point) Point {
+	return ctx.pixelSnapper()(p)
+}
+
+func (ctx *Context2D) pixelSnapper() func(Point) Point {
+	m := ctx.CurrentTransform()
+	inv, ok := m.Invert()
+	if !ok || m.B != 0 || m.C != 0 {
+		return func(p Point) Point { return p }
+	}
+	width := ctx.LineWidth * math.Sqrt(math.Abs(m.Determinant()))
+	odd := int(math.Max(1, math.Round(width)))%2 == 1
+	round := func(v float64) float64 {
+		if odd {
+			return math.Floor(v) + 0.5
+		}
+		return math.Round(v)
+	}
+	return func(p Point) Point {
+		d := m.TransformPoint(p)
+		return inv.TransformPoint(Point{X: round(d.X), Y: round(d.Y)})
+	}
+}
@@ -0,0 +1,193 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// SelectMode is the shape drawn by a SelectionTool.
+type SelectMode int
+
+const (
+	// SelectMarquee selects the rectangle spanned by the drag.
+	SelectMarquee SelectMode = iota
+	// SelectLasso selects the area enclosed by the freehand drag.
+	SelectLasso
+)
+
+// Selection is a closed polygonal area of the canvas, in canvas coordinates.
+type Selection struct {
+	Points []Point
+}
+
+// Empty reports whether the selection encloses no area.
+func (s *Selection) Empty() bool {
+	return s == nil || len(s.Points) < 3 || PolygonArea(s.Points) == 0
+}
+
+// Bounds returns the bounding box of the selection.
+func (s *Selection) Bounds() Rect {
+	return BoundingBox(s.Points)
+}
+
+// Contains reports whether p lies inside the selection.
+func (s *Selection) Contains(p Point) bool {
+	return PointInPolygon(p, s.Points)
+}
+
+// Path returns the outline of the selection as a closed Path2D.
+func (s *Selection) Path() *Path2D {
+	p := NewPath2D()
+	for i, pt := range s.Points {
+		if i == 0 {
+			p.MoveTo(pt.X, pt.Y)
+		} else {
+			p.LineTo(pt.X, pt.Y)
+		}
+	}
+	p.ClosePath()
+	return p
+}
+
+// Clip intersects the clip region of ctx with the selection.
+// Wrap it in Save/Restore to undo it.
+func (s *Selection) Clip(ctx *Context2D) {
+	ctx.ClipPath(s.Path())
+}
+
+// Mask returns a width x height canvas that is opaque white inside the selection
+// and transparent elsewhere.
+func (s *Selection) Mask(width, height int) *Canvas {
+	m := Create(width, height)
+	ctx := m.GetContext2D()
+	ctx.FillStyle = "#fff"
+	ctx.FillPath(s.Path())
+	return m
+}
+
+// Extract copies the selected pixels of ctx's canvas into an ImageData the size of
+// the selection bounds; pixels outside the selection are transparent.
+func (s *Selection) Extract(ctx *Context2D) *ImageData {
+	b := s.Bounds()
+	x, y := math.Floor(b.X), math.Floor(b.Y)
+	w, h := int(math.Ceil(b.X+b.W)-x), int(math.Ceil(b.Y+b.H)-y)
+	if w < 1 || h < 1 {
+		return ctx.CreateImageData(1, 1)
+	}
+	tmp := Create(w, h)
+	t := tmp.GetContext2D()
+	t.Translate(-x, -y)
+	s.Clip(t)
+	t.DrawImage(ctx.Canvas(), 0, 0, ctx.Canvas().Get("width").Float(), ctx.Canvas().Get("height").Float())
+	t.SetTransform(1, 0, 0, 1, 0, 0)
+	return t.GetImageData(0, 0, w, h)
+}
+
+// SelectionTool lets the user drag out a marquee or lasso selection on a canvas.
+// The selection is shown with a SelectionOutline on an overlay context, typically a
+// transparent canvas stacked above the content.
+type SelectionTool struct {
+	// Mode selects between marquee and lasso.
+	Mode SelectMode
+	// MinDistance is the minimum spacing of lasso points, 2 by default.
+	MinDistance float64
+	// OnSelect is called when a drag ends with the resulting selection,
+	// nil if the drag enclosed no area.
+	OnSelect func(sel *Selection)
+	// Outline draws the selection; its fields can be adjusted to change the look.
+	Outline *SelectionOutline
+
+	canvas   *Canvas
+	sel      *Selection
+	start    Point
+	dragging bool
+	remove   func()
+}
+
+// NewSelectionTool tracks pointer drags on c and draws the selection on overlay.
+// Call Detach to stop listening.
+func NewSelectionTool(c *Canvas, overlay *Context2D, mode SelectMode) *SelectionTool {
+	t := &SelectionTool{
+		Mode:        mode,
+		MinDistance: 2,
+		Outline:     NewSelectionOutline(overlay, nil),
+		canvas:      c,
+	}
+	t.remove = c.onPointerDrag(t.down, t.move, t.up)
+	return t
+}
+
+// Selection returns the current selection, nil if there is none.
+func (t *SelectionTool) Selection() *Selection {
+	return t.sel
+}
+
+// Clear removes the current selection and its outline.
+func (t *SelectionTool) Clear() {
+	t.sel = nil
+	t.dragging = false
+	t.Outline.Stop()
+	t.Outline.Path = nil
+	t.redraw()
+}
+
+// Detach clears the selection and removes the event listeners.
+func (t *SelectionTool) Detach() {
+	t.Clear()
+	t.remove()
+}
+
+func (t *SelectionTool) down(p Point, ev *js.Object) {
+	t.Clear()
+	t.canvas.Call("setPointerCapture", ev.Get("pointerId"))
+	t.dragging = true
+	t.start = p
+	t.sel = &Selection{Points: []Point{p}}
+}
+
+func (t *SelectionTool) move(p Point, ev *js.Object) {
+	if !t.dragging {
+		return
+	}
+	switch t.Mode {
+	case SelectMarquee:
+		t.sel.Points = []Point{t.start, {X: p.X, Y: t.start.Y}, p, {X: t.start.X, Y: p.Y}}
+	case SelectLasso:
+		last := t.sel.Points[len(t.sel.Points)-1]
+		if math.Hypot(p.X-last.X, p.Y-last.Y) < t.MinDistance {
+			return
+		}
+		t.sel.Points = append(t.sel.Points, p)
+	}
+	t.Outline.Path = t.sel.Path()
+	t.redraw()
+}
+
+func (t *SelectionTool) up(p Point, ev *js.Object) {
+	if !t.dragging {
+		return
+	}
+	t.dragging = false
+	if t.sel.Empty() {
+		t.sel = nil
+		t.Outline.Path = nil
+		t.redraw()
+	} else {
+		t.Outline.Path = t.sel.Path()
+		t.Outline.Start()
+	}
+	if t.OnSelect != nil {
+		t.OnSelect(t.sel)
+	}
+}
+
+func (t *SelectionTool) redraw() {
+	o := t.Outline
+	if o.Underlay != nil {
+		o.Underlay(o.ctx)
+	} else {
+		o.ctx.Clear()
+	}
+	o.Draw()
+}
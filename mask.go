@@ -0,0 +1,66 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// MaskMode selects how ApplyMask interprets the mask.
+type MaskMode int
+
+const (
+	// MaskAlpha keeps the content where the mask is opaque.
+	MaskAlpha MaskMode = iota
+	// MaskAlphaInverse keeps the content where the mask is transparent.
+	MaskAlphaInverse
+	// MaskLuminance keeps the content where the mask is bright, weighted by its alpha,
+	// like an SVG luminance mask: white keeps, black removes.
+	MaskLuminance
+	// MaskLuminanceInverse keeps the content where the mask is dark.
+	MaskLuminanceInverse
+)
+
+// ApplyMask cuts the content of ctx's canvas with mask, which is stretched over the
+// whole canvas regardless of the current transform. Alpha masks are applied with
+// destination-in and destination-out compositing; luminance masks are first converted
+// to alpha masks with LuminanceMask.
+func ApplyMask(ctx *Context2D, mask CanvasImageSource, mode MaskMode) {
+	op := CompositeDestinationIn
+	switch mode {
+	case MaskAlphaInverse:
+		op = CompositeDestinationOut
+	case MaskLuminance:
+		mask = LuminanceMask(mask, false)
+	case MaskLuminanceInverse:
+		mask = LuminanceMask(mask, true)
+	}
+	w, h := ctx.Canvas().Size()
+	ctx.Save()
+	ctx.SetTransform(1, 0, 0, 1, 0, 0)
+	ctx.GlobalAlpha = 1
+	ctx.SetCompositeOp(op)
+	ctx.DrawImage(mask, 0, 0, float64(w), float64(h))
+	ctx.Restore()
+}
+
+// LuminanceMask converts src into an alpha mask: a white canvas whose alpha is the
+// luma of each pixel of src times its alpha, or one minus the luma if invert is set.
+func LuminanceMask(src CanvasImageSource, invert bool) *Canvas {
+	sw, sh := SourceSize(src)
+	w, h := int(sw), int(sh)
+	if w < 1 || h < 1 {
+		return Create(1, 1)
+	}
+	out := Create(w, h)
+	ctx := out.GetContext2D(WithContextAttributes(js.M{"willReadFrequently": true}))
+	ctx.DrawImage(src, 0, 0, sw, sh)
+	img := ctx.GetImageData(0, 0, w, h)
+	b := img.View()
+	for o := 0; o < len(b); o += 4 {
+		l := uint32(Luma(b[o], b[o+1], b[o+2]))
+		if invert {
+			l = 255 - l
+		}
+		b[o], b[o+1], b[o+2] = 255, 255, 255
+		b[o+3] = uint8((l*uint32(b[o+3]) + 127) / 255)
+	}
+	ctx.PutImageData(img, 0, 0)
+	return out
+}
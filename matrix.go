@@ -0,0 +1,132 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Matrix is a 2D affine transform in the form used by the canvas API:
+//
+//	| A C E |
+//	| B D F |
+//	| 0 0 1 |
+//
+// mapping a point (x, y) to (A*x + C*y + E, B*x + D*y + F).
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity returns the identity matrix.
+func Identity() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// Translation returns a matrix translating by x, y.
+func Translation(x, y float64) Matrix {
+	return Matrix{A: 1, D: 1, E: x, F: y}
+}
+
+// Scaling returns a matrix scaling by sx, sy.
+func Scaling(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+// Rotation returns a matrix rotating by angle radians, clockwise on screen.
+func Rotation(angle float64) Matrix {
+	sin, cos := math.Sincos(angle)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// MatrixOf returns the matrix of the a, b, c, d, e, f values of SetTransform.
+func MatrixOf(v [6]float64) Matrix {
+	return Matrix{v[0], v[1], v[2], v[3], v[4], v[5]}
+}
+
+// Array returns the matrix as the a, b, c, d, e, f values of SetTransform.
+func (m Matrix) Array() [6]float64 {
+	return [6]float64{m.A, m.B, m.C, m.D, m.E, m.F}
+}
+
+// Multiply returns m × n, the transform applying n first and then m. This is what
+// Context2D.Transform does to the current transform: the context transform becomes
+// current.Multiply(n).
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		A: m.A*n.A + m.C*n.B,
+		B: m.B*n.A + m.D*n.B,
+		C: m.A*n.C + m.C*n.D,
+		D: m.B*n.C + m.D*n.D,
+		E: m.A*n.E + m.C*n.F + m.E,
+		F: m.B*n.E + m.D*n.F + m.F,
+	}
+}
+
+// Translate returns m with a translation applied first, like Context2D.Translate.
+func (m Matrix) Translate(x, y float64) Matrix {
+	return m.Multiply(Translation(x, y))
+}
+
+// Scale returns m with a scaling applied first, like Context2D.Scale.
+func (m Matrix) Scale(sx, sy float64) Matrix {
+	return m.Multiply(Scaling(sx, sy))
+}
+
+// Rotate returns m with a rotation applied first, like Context2D.Rotate.
+func (m Matrix) Rotate(angle float64) Matrix {
+	return m.Multiply(Rotation(angle))
+}
+
+// Determinant returns the determinant of the linear part of m.
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// Invert returns the inverse of m, and false if m is singular.
+func (m Matrix) Invert() (Matrix, bool) {
+	det := m.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Matrix{}, false
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, true
+}
+
+// TransformPoint applies m to p.
+func (m Matrix) TransformPoint(p Point) Point {
+	return Point{X: m.A*p.X + m.C*p.Y + m.E, Y: m.B*p.X + m.D*p.Y + m.F}
+}
+
+// TransformVector applies the linear part of m to v, ignoring the translation.
+func (m Matrix) TransformVector(v Point) Point {
+	return Point{X: m.A*v.X + m.C*v.Y, Y: m.B*v.X + m.D*v.Y}
+}
+
+// SetMatrix replaces the current transform with m.
+func (ctx *Context2D) SetMatrix(m Matrix) {
+	ctx.SetTransform(m.A, m.B, m.C, m.D, m.E, m.F)
+}
+
+// TransformBy multiplies the current transform by m.
+func (ctx *Context2D) TransformBy(m Matrix) {
+	ctx.Transform(m.A, m.B, m.C, m.D, m.E, m.F)
+}
+
+// GetMatrix returns the current transform as reported by getTransform, or the
+// identity in browsers that lack it.
+func (ctx *Context2D) GetMatrix() Matrix {
+	if ctx.Get("getTransform") == js.Undefined {
+		return Identity()
+	}
+	t := ctx.Call("getTransform")
+	return Matrix{
+		t.Get("a").Float(), t.Get("b").Float(), t.Get("c").Float(),
+		t.Get("d").Float(), t.Get("e").Float(), t.Get("f").Float(),
+	}
+}
@@ -0,0 +1,82 @@
+package canvas
+
+import (
+	"math"
+	"testing"
+)
+
+func matrixNear(a, b Matrix) bool {
+	av, bv := a.Array(), b.Array()
+	for i := range av {
+		if math.Abs(av[i]-bv[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMatrixMultiply(t *testing.T) {
+	tests := []struct {
+		name string
+		m, n Matrix
+		p    Point
+		want Point
+	}{
+		{"identity", Identity(), Identity(), Pt(3, 4), Pt(3, 4)},
+		{"translate then scale", Scaling(2, 3), Translation(1, 1), Pt(1, 2), Pt(4, 9)},
+		{"scale then translate", Translation(1, 1), Scaling(2, 3), Pt(1, 2), Pt(3, 7)},
+		{"rotate then translate", Translation(10, 0), Rotation(math.Pi / 2), Pt(1, 0), Pt(10, 1)},
+		{"translate then rotate", Rotation(math.Pi / 2), Translation(10, 0), Pt(1, 0), Pt(0, 11)},
+		{"shear", Matrix{A: 1, C: 2, D: 1}, Scaling(2, 2), Pt(1, 1), Pt(6, 2)},
+	}
+	for _, tt := range tests {
+		got := tt.m.Multiply(tt.n).TransformPoint(tt.p)
+		if math.Abs(got.X-tt.want.X) > 1e-9 || math.Abs(got.Y-tt.want.Y) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		// m × n must agree with applying n and then m.
+		seq := tt.m.TransformPoint(tt.n.TransformPoint(tt.p))
+		if math.Abs(got.X-seq.X) > 1e-9 || math.Abs(got.Y-seq.Y) > 1e-9 {
+			t.Errorf("%s: product gives %v, sequential gives %v", tt.name, got, seq)
+		}
+	}
+}
+
+func TestMatrixInvert(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Matrix
+		ok   bool
+	}{
+		{"identity", Identity(), true},
+		{"translation", Translation(5, -7), true},
+		{"scaling", Scaling(2, 0.5), true},
+		{"rotation", Rotation(0.7), true},
+		{"general", Matrix{A: 2, B: 1, C: -1, D: 3, E: 4, F: 5}, true},
+		{"mirror", Scaling(-1, 1), true},
+		{"zero", Matrix{}, false},
+		{"zero x scale", Scaling(0, 2), false},
+		{"collinear columns", Matrix{A: 1, B: 2, C: 2, D: 4, E: 1}, false},
+		{"NaN", Matrix{A: math.NaN(), D: 1}, false},
+		{"infinite", Matrix{A: math.Inf(1), D: 1}, false},
+	}
+	for _, tt := range tests {
+		inv, ok := tt.m.Invert()
+		if ok != tt.ok {
+			t.Errorf("%s: Invert ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			if inv != (Matrix{}) {
+				t.Errorf("%s: singular Invert = %v, want zero matrix", tt.name, inv)
+			}
+			continue
+		}
+		if got := tt.m.Multiply(inv); !matrixNear(got, Identity()) {
+			t.Errorf("%s: m × inverse = %v, want identity", tt.name, got)
+		}
+		if got := inv.Multiply(tt.m); !matrixNear(got, Identity()) {
+			t.Errorf("%s: inverse × m = %v, want identity", tt.name, got)
+		}
+	}
+}
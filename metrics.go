@@ -0,0 +1,190 @@
+package canvas
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Metrics counts the calls made on a context, per method, to find out what a frame
+// spends its time on. It is created by Context2D.EnableMetrics, which shadows every
+// method and property of the underlying JavaScript context object with a counting
+// wrapper, so calls are counted whether they are made through this package or
+// directly through the *js.Object. Counting adds overhead of its own; disable it
+// when not measuring.
+type Metrics struct {
+	ctx     *Context2D
+	id      int
+	names   []string
+	counts  map[string]int
+	last    FrameMetrics
+	enabled bool
+	paused  bool
+}
+
+// FrameMetrics are the counters of one frame.
+type FrameMetrics struct {
+	// Counts maps method names to the number of calls. Property assignments are
+	// counted as "set " followed by the property name, e.g. "set fillStyle".
+	Counts map[string]int
+	// Total is the sum of all counts.
+	Total int
+	// DrawCalls counts the calls that touch pixels: fills, strokes, text, images and clears.
+	DrawCalls int
+	// StateChanges counts property assignments and save, restore and transform calls.
+	StateChanges int
+}
+
+// MethodCount is a method name and its number of calls.
+type MethodCount struct {
+	Name  string
+	Count int
+}
+
+// Top returns the n most frequently called methods, most frequent first.
+func (f FrameMetrics) Top(n int) []MethodCount {
+	all := make([]MethodCount, 0, len(f.Counts))
+	for k, v := range f.Counts {
+		all = append(all, MethodCount{k, v})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Count != all[j].Count {
+			return all[i].Count > all[j].Count
+		}
+		return all[i].Name < all[j].Name
+	})
+	if n >= 0 && n < len(all) {
+		all = all[:n]
+	}
+	return all
+}
+
+var drawMethods = map[string]bool{
+	"fill": true, "stroke": true, "fillRect": true, "strokeRect": true, "clearRect": true,
+	"fillText": true, "strokeText": true, "drawImage": true, "putImageData": true,
+}
+
+var stateMethods = map[string]bool{
+	"save": true, "restore": true, "scale": true, "rotate": true, "translate": true,
+	"transform": true, "setTransform": true, "resetTransform": true, "setLineDash": true,
+}
+
+// EnableMetrics starts counting calls on ctx. Calling it again returns the same Metrics.
+func (ctx *Context2D) EnableMetrics() *Metrics {
+	// the id is kept on the JavaScript object so every wrapper of the context finds it
+	if v := ctx.Get(metricsKey); v != js.Undefined && v != nil {
+		if m, ok := metricsByID[v.Int()]; ok {
+			return m
+		}
+	}
+	lastMetricsID++
+	m := &Metrics{ctx: ctx, id: lastMetricsID, counts: map[string]int{}}
+	m.enable()
+	metricsByID[m.id] = m
+	ctx.Set(metricsKey, m.id)
+	return m
+}
+
+const metricsKey = "__goCanvasMetrics"
+
+var (
+	metricsByID   = map[int]*Metrics{}
+	lastMetricsID int
+)
+
+func (m *Metrics) enable() {
+	object := js.Global.Get("Object")
+	proto := object.Call("getPrototypeOf", m.ctx.Object)
+	names := object.Call("getOwnPropertyNames", proto)
+	for i := 0; i < names.Length(); i++ {
+		name := names.Index(i).String()
+		if name == "constructor" || name == "canvas" {
+			continue
+		}
+		desc := object.Call("getOwnPropertyDescriptor", proto, name)
+		switch {
+		case desc.Get("set") != js.Undefined && desc.Get("set") != nil:
+			m.shadowProperty(name, desc)
+		case isFunction(desc.Get("value")):
+			m.shadowMethod(name, desc.Get("value"))
+		default:
+			continue
+		}
+		m.names = append(m.names, name)
+	}
+	m.enabled = true
+}
+
+func isFunction(v *js.Object) bool {
+	return v != js.Undefined && v != nil && js.Global.Get("Function").Get("prototype").Call("isPrototypeOf", v).Bool()
+}
+
+func (m *Metrics) shadowMethod(name string, fn *js.Object) {
+	m.ctx.Set(name, js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		if !m.paused {
+			m.counts[name]++
+		}
+		return fn.Call("apply", this, args)
+	}))
+}
+
+func (m *Metrics) shadowProperty(name string, desc *js.Object) {
+	get, set := desc.Get("get"), desc.Get("set")
+	key := "set " + name
+	js.Global.Get("Object").Call("defineProperty", m.ctx.Object, name, js.M{
+		"configurable": true,
+		"get": js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+			return get.Call("call", this)
+		}),
+		"set": js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+			if !m.paused {
+				m.counts[key]++
+			}
+			return set.Call("call", this, args[0])
+		}),
+	})
+}
+
+// Disable stops counting and removes the wrappers from the context.
+func (m *Metrics) Disable() {
+	if !m.enabled {
+		return
+	}
+	for _, name := range m.names {
+		m.ctx.Delete(name)
+	}
+	m.names = nil
+	m.enabled = false
+	m.ctx.Delete(metricsKey)
+	delete(metricsByID, m.id)
+}
+
+// Current returns the counters of the frame in progress.
+func (m *Metrics) Current() FrameMetrics {
+	f := FrameMetrics{Counts: make(map[string]int, len(m.counts))}
+	for k, v := range m.counts {
+		f.Counts[k] = v
+		f.Total += v
+		switch {
+		case drawMethods[k]:
+			f.DrawCalls += v
+		case stateMethods[k] || strings.HasPrefix(k, "set "):
+			f.StateChanges += v
+		}
+	}
+	return f
+}
+
+// EndFrame closes the current frame, returning its counters and resetting them for
+// the next one. Call it once per frame, after drawing.
+func (m *Metrics) EndFrame() FrameMetrics {
+	m.last = m.Current()
+	m.counts = map[string]int{}
+	return m.last
+}
+
+// Last returns the counters of the frame last closed by EndFrame.
+func (m *Metrics) Last() FrameMetrics {
+	return m.last
+}
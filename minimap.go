@@ -0,0 +1,134 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Minimap renders a scaled-down overview of a world into its own canvas, marks the
+// area visible through a Camera and lets the user click or drag on it to move the
+// camera there.
+type Minimap struct {
+	// World is the area shown by the minimap, in world units.
+	World Rect
+	// DrawScene draws the scene in world coordinates onto the minimap context.
+	// If nil, Source is drawn instead.
+	DrawScene func(ctx *Context2D)
+	// Source, if DrawScene is nil, is an image of World, such as the main canvas
+	// when the camera shows the whole world or the flattened layers of a document.
+	Source CanvasImageSource
+	// Background, ViewportColor and ViewportFill style the minimap.
+	Background, ViewportColor, ViewportFill string
+	// OnNavigate is called after the camera has been moved by the minimap.
+	OnNavigate func(cam *Camera)
+
+	canvas *Canvas
+	ctx    *Context2D
+	cam    *Camera
+	drag   bool
+	remove func()
+}
+
+// NewMinimap creates a minimap drawing onto c, showing world and the viewport of cam.
+// Call Detach to stop listening to pointer events.
+func NewMinimap(c *Canvas, cam *Camera, world Rect) *Minimap {
+	m := &Minimap{
+		World:         world,
+		Background:    "#fafafa",
+		ViewportColor: "#e53935",
+		ViewportFill:  "rgba(229,57,53,0.1)",
+		canvas:        c,
+		ctx:           c.GetContext2D(),
+		cam:           cam,
+	}
+	m.remove = c.onPointerDrag(
+		func(p Point, ev *js.Object) {
+			c.Call("setPointerCapture", ev.Get("pointerId"))
+			m.drag = true
+			m.navigate(p)
+		},
+		func(p Point, ev *js.Object) {
+			if m.drag {
+				m.navigate(p)
+			}
+		},
+		func(p Point, ev *js.Object) { m.drag = false },
+	)
+	return m
+}
+
+// Detach removes the event listeners.
+func (m *Minimap) Detach() {
+	m.remove()
+}
+
+// Matrix returns the world-to-minimap transform, fitting World into the minimap
+// canvas while preserving its aspect ratio.
+func (m *Minimap) Matrix() Matrix {
+	pw, ph := m.canvas.Size()
+	r := m.canvas.PixelRatio()
+	w, h := float64(pw)/r, float64(ph)/r
+	if m.World.W <= 0 || m.World.H <= 0 {
+		return Identity()
+	}
+	s := math.Min(w/m.World.W, h/m.World.H)
+	ox := (w - m.World.W*s) / 2
+	oy := (h - m.World.H*s) / 2
+	return Translation(ox, oy).Scale(s, s).Translate(-m.World.X, -m.World.Y)
+}
+
+// Draw repaints the minimap: the scene and the viewport of the camera on top.
+func (m *Minimap) Draw() {
+	ctx := m.ctx
+	ctx.Clear()
+	mat := m.Matrix()
+	ctx.Save()
+	ctx.TransformBy(mat)
+	ctx.FillStyle = m.Background
+	ctx.FillRect(m.World.X, m.World.Y, m.World.W, m.World.H)
+	ctx.Save()
+	m.World.ClipOn(ctx)
+	if m.DrawScene != nil {
+		m.DrawScene(ctx)
+	} else if m.Source != nil {
+		ctx.DrawImage(m.Source, m.World.X, m.World.Y, m.World.W, m.World.H)
+	}
+	ctx.Restore()
+	ctx.Restore()
+
+	cam := m.cam
+	corners := []Point{
+		cam.ScreenToWorld(Point{}),
+		cam.ScreenToWorld(Point{X: cam.ViewWidth}),
+		cam.ScreenToWorld(Point{X: cam.ViewWidth, Y: cam.ViewHeight}),
+		cam.ScreenToWorld(Point{Y: cam.ViewHeight}),
+	}
+	for i, p := range corners {
+		corners[i] = mat.TransformPoint(p)
+	}
+	ctx.Save()
+	ctx.BeginPath()
+	tracePolyline(ctx, corners)
+	ctx.ClosePath()
+	ctx.FillStyle = m.ViewportFill
+	ctx.Fill()
+	ctx.StrokeStyle = m.ViewportColor
+	ctx.LineWidth = 1.5
+	ctx.Stroke()
+	ctx.Restore()
+}
+
+// navigate centres the camera on the world point under the minimap point p.
+func (m *Minimap) navigate(p Point) {
+	inv, ok := m.Matrix().Invert()
+	if !ok {
+		return
+	}
+	w := inv.TransformPoint(p)
+	m.cam.X, m.cam.Y = w.X, w.Y
+	m.Draw()
+	if m.OnNavigate != nil {
+		m.OnNavigate(m.cam)
+	}
+}
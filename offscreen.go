@@ -0,0 +1,204 @@
+package canvas
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ErrOffscreenUnsupported is returned when the browser lacks OffscreenCanvas or
+// the bitmaprenderer context.
+var ErrOffscreenUnsupported = errors.New("canvas: OffscreenCanvas not supported")
+
+// OffscreenCanvas is a canvas detached from the DOM that can also be used inside a
+// Web Worker, so rendering doesn't compete with the main thread.
+type OffscreenCanvas struct {
+	*js.Object
+	ctx *Context2D
+}
+
+// NewOffscreenCanvas creates an OffscreenCanvas with a backing store of width×height pixels.
+func NewOffscreenCanvas(width, height int) (*OffscreenCanvas, error) {
+	ctor := js.Global.Get("OffscreenCanvas")
+	if ctor == js.Undefined {
+		return nil, ErrOffscreenUnsupported
+	}
+	return &OffscreenCanvas{Object: ctor.New(width, height)}, nil
+}
+
+// ImageSource returns the OffscreenCanvas, which can be drawn like any other canvas.
+func (c *OffscreenCanvas) ImageSource() *js.Object {
+	return c.Object
+}
+
+// Size returns the size of the backing store in pixels.
+func (c *OffscreenCanvas) Size() (width, height int) {
+	return c.Get("width").Int(), c.Get("height").Int()
+}
+
+// SetSize resizes the backing store, clearing it and resetting the context state.
+func (c *OffscreenCanvas) SetSize(width, height int) {
+	c.Set("width", width)
+	c.Set("height", height)
+}
+
+// GetContext2D returns the 2D context of the canvas; Context2D.Canvas must not be
+// called on it since the canvas is not an element.
+func (c *OffscreenCanvas) GetContext2D() *Context2D {
+	if c.ctx == nil {
+		c.ctx = &Context2D{Object: c.Call("getContext", "2d")}
+	}
+	return c.ctx
+}
+
+// TransferToImageBitmap moves the current contents into a new ImageBitmap without
+// copying and leaves the canvas cleared for the next frame.
+func (c *OffscreenCanvas) TransferToImageBitmap() *ImageBitmap {
+	return &ImageBitmap{Object: c.Call("transferToImageBitmap")}
+}
+
+const (
+	frameMessage    = "gopherjs-canvas-frame"
+	frameAckMessage = "gopherjs-canvas-frame-ack"
+)
+
+// FrameProducer renders frames into an OffscreenCanvas, typically inside a Web Worker,
+// and posts each as an ImageBitmap to a BitmapPresenter on the main thread. The bitmap
+// is transferred, not copied. While the presenter hasn't shown the previous frame new
+// frames are dropped, so a slow main thread doesn't build up a queue.
+//
+// In the worker:
+//
+//	p, err := canvas.NewFrameProducer(js.Global, 640, 480)
+//	p.Run(func(ctx *canvas.Context2D, f canvas.FrameInfo) { draw(ctx, f) })
+//
+// On the main thread:
+//
+//	worker := js.Global.Get("Worker").New("render.js")
+//	pr, err := canvas.NewBitmapPresenter(c, worker)
+type FrameProducer struct {
+	// Canvas is the canvas frames are rendered into.
+	Canvas *OffscreenCanvas
+	// Dropped counts the frames that were rendered but not posted because the
+	// presenter was still busy.
+	Dropped int
+
+	port     *js.Object
+	listener *js.Object
+	busy     bool
+	ticker   *Ticker
+}
+
+// NewFrameProducer creates a producer rendering width×height frames and posting them
+// to port: js.Global inside a dedicated worker, or a MessagePort.
+func NewFrameProducer(port *js.Object, width, height int) (*FrameProducer, error) {
+	c, err := NewOffscreenCanvas(width, height)
+	if err != nil {
+		return nil, err
+	}
+	p := &FrameProducer{Canvas: c, port: port}
+	p.listener = js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		if d := args[0].Get("data"); d != nil && d != js.Undefined && d.Get("type").String() == frameAckMessage {
+			p.busy = false
+		}
+		return nil
+	})
+	port.Call("addEventListener", "message", p.listener)
+	if port.Get("start") != js.Undefined {
+		// MessagePorts deliver nothing until started
+		port.Call("start")
+	}
+	return p, nil
+}
+
+// Context returns the context to render the next frame with.
+func (p *FrameProducer) Context() *Context2D {
+	return p.Canvas.GetContext2D()
+}
+
+// Present posts the current contents of the canvas as a frame and reports whether it
+// was sent. The canvas is cleared afterwards either way.
+func (p *FrameProducer) Present() bool {
+	bmp := p.Canvas.TransferToImageBitmap()
+	if p.busy {
+		bmp.Close()
+		p.Dropped++
+		return false
+	}
+	p.busy = true
+	p.port.Call("postMessage", js.M{"type": frameMessage, "bitmap": bmp.Object}, []interface{}{bmp.Object})
+	return true
+}
+
+// Run renders with draw and presents the result on every animation frame until Stop.
+// Rendering is skipped entirely while the presenter is busy.
+func (p *FrameProducer) Run(draw func(ctx *Context2D, f FrameInfo)) {
+	p.Stop()
+	p.ticker = NewTicker(func(f FrameInfo) {
+		if p.busy {
+			p.Dropped++
+			return
+		}
+		draw(p.Context(), f)
+		p.Present()
+	})
+	p.ticker.Start()
+}
+
+// Stop ends the loop started by Run.
+func (p *FrameProducer) Stop() {
+	if p.ticker != nil {
+		p.ticker.Stop()
+		p.ticker = nil
+	}
+}
+
+// Close stops the producer and stops listening on its port.
+func (p *FrameProducer) Close() {
+	p.Stop()
+	p.port.Call("removeEventListener", "message", p.listener)
+}
+
+// BitmapPresenter shows the frames of a FrameProducer on a visible canvas through its
+// bitmaprenderer context, which takes ownership of each bitmap without copying.
+type BitmapPresenter struct {
+	// OnFrame, if not nil, is called after every presented frame.
+	OnFrame func()
+
+	ctx      *js.Object
+	port     *js.Object
+	listener *js.Object
+}
+
+// NewBitmapPresenter displays the frames arriving from port, usually the Worker running
+// the FrameProducer, on c. c must not have a 2D context; its backing store takes the
+// size of the frames.
+func NewBitmapPresenter(c *Canvas, port *js.Object) (*BitmapPresenter, error) {
+	ctx := c.Call("getContext", "bitmaprenderer")
+	if ctx == nil {
+		return nil, ErrOffscreenUnsupported
+	}
+	p := &BitmapPresenter{ctx: ctx, port: port}
+	p.listener = js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		d := args[0].Get("data")
+		if d == nil || d == js.Undefined || d.Get("type").String() != frameMessage {
+			return nil
+		}
+		p.ctx.Call("transferFromImageBitmap", d.Get("bitmap"))
+		p.port.Call("postMessage", js.M{"type": frameAckMessage})
+		if p.OnFrame != nil {
+			p.OnFrame()
+		}
+		return nil
+	})
+	port.Call("addEventListener", "message", p.listener)
+	if port.Get("start") != js.Undefined {
+		port.Call("start")
+	}
+	return p, nil
+}
+
+// Close stops presenting frames.
+func (p *BitmapPresenter) Close() {
+	p.port.Call("removeEventListener", "message", p.listener)
+}
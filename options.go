@@ -0,0 +1,162 @@
+package canvas
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Option configures a Canvas in New or its context in GetContext2D.
+type Option func(*options)
+
+type options struct {
+	width, height int
+	hiDPI         bool
+	attrs         js.M
+	autoResize    bool
+	onResize      func(c *Canvas)
+	preserve      bool
+	preserveMode  ScaleMode
+}
+
+// WithSize sets the size of the canvas in CSS pixels.
+// Combined with WithHiDPI the backing store is scaled by the device pixel ratio.
+func WithSize(width, height int) Option {
+	return func(o *options) {
+		o.width, o.height = width, height
+	}
+}
+
+// WithHiDPI scales the backing store of the canvas by window.devicePixelRatio
+// and the context transform by the same factor, so drawing in CSS pixel units
+// stays sharp on high density displays.
+func WithHiDPI() Option {
+	return func(o *options) {
+		o.hiDPI = true
+	}
+}
+
+// WithContextAttributes passes attributes such as {"alpha": false} or
+// {"willReadFrequently": true} to getContext("2d").
+func WithContextAttributes(attrs js.M) Option {
+	return func(o *options) {
+		o.attrs = attrs
+	}
+}
+
+// WithAutoResize keeps the backing store of the canvas in sync with its laid out CSS size
+// using a ResizeObserver. onResize, if not nil, is called after every resize;
+// resizing clears the canvas, so it is the place to redraw, unless WithPreserveContent is given too.
+func WithAutoResize(onResize func(c *Canvas)) Option {
+	return func(o *options) {
+		o.autoResize = true
+		o.onResize = onResize
+	}
+}
+
+func (c *Canvas) apply(opts []Option) {
+	if len(opts) == 0 {
+		return
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c.ctxAttrs = o.attrs
+	if o.hiDPI {
+		c.pixelRatio = devicePixelRatio()
+	}
+	switch {
+	case o.width > 0 && o.height > 0:
+		c.SetSize(o.width, o.height)
+	case o.hiDPI:
+		c.SetSize(c.Get("width").Int(), c.Get("height").Int())
+	}
+	if o.autoResize {
+		c.onResize = o.onResize
+		if o.preserve {
+			c.preserve = &o.preserveMode
+		}
+		c.observeResize()
+	}
+}
+
+// PixelRatio returns the number of backing store pixels per CSS pixel,
+// 1 unless the canvas was created WithHiDPI.
+func (c *Canvas) PixelRatio() float64 {
+	if c.pixelRatio <= 0 {
+		return 1
+	}
+	return c.pixelRatio
+}
+
+// Size returns the size of the backing store in pixels.
+func (c *Canvas) Size() (width, height int) {
+	return c.Get("width").Int(), c.Get("height").Int()
+}
+
+// SetSize sets the size of the canvas in CSS pixels, scaling the backing store by
+// PixelRatio. Like any change of the canvas size it clears the canvas and resets the context state.
+func (c *Canvas) SetSize(width, height int) {
+	ratio := c.PixelRatio()
+	if ratio != 1 {
+		style := c.Get("style")
+		style.Set("width", strconv.Itoa(width)+"px")
+		style.Set("height", strconv.Itoa(height)+"px")
+	}
+	c.Set("width", int(math.Round(float64(width)*ratio)))
+	c.Set("height", int(math.Round(float64(height)*ratio)))
+	c.applyPixelRatio()
+}
+
+// applyPixelRatio scales the context so one unit equals one CSS pixel.
+func (c *Canvas) applyPixelRatio() {
+	if c.ctx != nil && c.ctx.xf != nil {
+		// resizing reset the context state
+		c.ctx.xf = &transformStack{current: Identity()}
+	}
+	if r := c.PixelRatio(); c.ctx != nil && r != 1 {
+		c.ctx.SetTransform(r, 0, 0, r, 0, 0)
+	}
+}
+
+func (c *Canvas) observeResize() {
+	ctor := js.Global.Get("ResizeObserver")
+	if ctor == js.Undefined {
+		return
+	}
+	c.observer = ctor.New(func(entries *js.Object) {
+		w, h := c.Get("clientWidth").Int(), c.Get("clientHeight").Int()
+		if w == 0 || h == 0 {
+			return
+		}
+		r := c.PixelRatio()
+		nw, nh := int(math.Round(float64(w)*r)), int(math.Round(float64(h)*r))
+		if bw, bh := c.Size(); bw == nw && bh == nh {
+			return
+		}
+		var snap *Canvas
+		if c.preserve != nil {
+			snap = c.snapshot()
+		}
+		c.Set("width", nw)
+		c.Set("height", nh)
+		c.applyPixelRatio()
+		if snap != nil {
+			c.restoreSnapshot(snap, *c.preserve)
+		}
+		if c.onResize != nil {
+			c.onResize(c)
+		}
+	})
+	c.observer.Call("observe", c.Object)
+}
+
+// StopAutoResize disconnects the resize observer installed by WithAutoResize.
+func (c *Canvas) StopAutoResize() {
+	if c.observer != nil {
+		c.observer.Call("disconnect")
+		c.observer = nil
+	}
+}
@@ -0,0 +1,152 @@
+package canvas
+
+// Path is a path held in Go, with the same drawing methods as Path2D.
+// Unlike Path2D its geometry can be inspected: Flatten turns it into polylines for
+// hit testing, dashing or exporting, and AppendTo replays it onto a context.
+type Path struct {
+	cmds []pathCmd
+}
+
+type pathOp int
+
+const (
+	opMoveTo pathOp = iota
+	opLineTo
+	opQuadTo
+	opCubicTo
+	opArc
+	opClose
+)
+
+type pathCmd struct {
+	op  pathOp
+	p   [3]Point
+	arc [4]float64 // radius, start and end angle, anticlockwise (1) or not (0)
+}
+
+// NewPath creates an empty Path.
+func NewPath() *Path {
+	return &Path{}
+}
+
+// MoveTo starts a new sub-path at (x, y).
+func (p *Path) MoveTo(x, y float64) {
+	p.cmds = append(p.cmds, pathCmd{op: opMoveTo, p: [3]Point{{x, y}}})
+}
+
+// LineTo adds a straight line to (x, y).
+func (p *Path) LineTo(x, y float64) {
+	p.cmds = append(p.cmds, pathCmd{op: opLineTo, p: [3]Point{{x, y}}})
+}
+
+// QuadraticCurveTo adds a quadratic Bézier curve.
+func (p *Path) QuadraticCurveTo(cpx, cpy, x, y float64) {
+	p.cmds = append(p.cmds, pathCmd{op: opQuadTo, p: [3]Point{{cpx, cpy}, {x, y}}})
+}
+
+// BezierCurveTo adds a cubic Bézier curve.
+func (p *Path) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) {
+	p.cmds = append(p.cmds, pathCmd{op: opCubicTo, p: [3]Point{{cp1x, cp1y}, {cp2x, cp2y}, {x, y}}})
+}
+
+// Arc adds an arc centered at (x, y), connected to the current point by a straight line.
+func (p *Path) Arc(x, y, radius, sAngle, eAngle float64, counterclockwise ...bool) {
+	ccw := 0.0
+	if len(counterclockwise) > 0 && counterclockwise[0] {
+		ccw = 1
+	}
+	p.cmds = append(p.cmds, pathCmd{op: opArc, p: [3]Point{{x, y}}, arc: [4]float64{radius, sAngle, eAngle, ccw}})
+}
+
+// Rect adds a closed rectangular sub-path.
+func (p *Path) Rect(x, y, width, height float64) {
+	p.MoveTo(x, y)
+	p.LineTo(x+width, y)
+	p.LineTo(x+width, y+height)
+	p.LineTo(x, y+height)
+	p.ClosePath()
+}
+
+// ClosePath closes the current sub-path.
+func (p *Path) ClosePath() {
+	p.cmds = append(p.cmds, pathCmd{op: opClose})
+}
+
+// Empty reports whether nothing has been added to the path.
+func (p *Path) Empty() bool {
+	return len(p.cmds) == 0
+}
+
+// AppendTo adds the path to the current path of ctx.
+func (p *Path) AppendTo(ctx *Context2D) {
+	for _, c := range p.cmds {
+		switch c.op {
+		case opMoveTo:
+			ctx.MoveTo(c.p[0].X, c.p[0].Y)
+		case opLineTo:
+			ctx.LineTo(c.p[0].X, c.p[0].Y)
+		case opQuadTo:
+			ctx.QuadraticCurveTo(c.p[0].X, c.p[0].Y, c.p[1].X, c.p[1].Y)
+		case opCubicTo:
+			ctx.BezierCurveTo(c.p[0].X, c.p[0].Y, c.p[1].X, c.p[1].Y, c.p[2].X, c.p[2].Y)
+		case opArc:
+			ctx.Arc(c.p[0].X, c.p[0].Y, c.arc[0], c.arc[1], c.arc[2], c.arc[3] != 0)
+		case opClose:
+			ctx.ClosePath()
+		}
+	}
+}
+
+// Flatten approximates the path by one polyline per sub-path, deviating at most tolerance
+// from curves. Closed sub-paths end with their first point repeated.
+func (p *Path) Flatten(tolerance float64) [][]Point {
+	var out [][]Point
+	var cur []Point
+	flush := func() {
+		if len(cur) > 1 {
+			out = append(out, cur)
+		}
+		cur = nil
+	}
+	last := func() Point {
+		if len(cur) == 0 {
+			return Point{}
+		}
+		return cur[len(cur)-1]
+	}
+	for _, c := range p.cmds {
+		switch c.op {
+		case opMoveTo:
+			flush()
+			cur = []Point{c.p[0]}
+		case opLineTo:
+			if len(cur) == 0 {
+				cur = []Point{c.p[0]}
+				continue
+			}
+			cur = append(cur, c.p[0])
+		case opQuadTo:
+			if len(cur) == 0 {
+				cur = []Point{c.p[0]}
+			}
+			cur = append(cur, FlattenQuadratic(last(), c.p[0], c.p[1], tolerance)[1:]...)
+		case opCubicTo:
+			if len(cur) == 0 {
+				cur = []Point{c.p[0]}
+			}
+			cur = append(cur, FlattenCubic(last(), c.p[0], c.p[1], c.p[2], tolerance)[1:]...)
+		case opArc:
+			arc := FlattenArc(c.p[0].X, c.p[0].Y, c.arc[0], c.arc[1], c.arc[2], c.arc[3] != 0, tolerance)
+			cur = append(cur, arc...)
+		case opClose:
+			if len(cur) > 0 {
+				first := cur[0]
+				cur = append(cur, first)
+				flush()
+				cur = []Point{first}
+			}
+		}
+	}
+	flush()
+	return out
+}
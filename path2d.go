@@ -0,0 +1,87 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// Path2D The Path2D interface of the Canvas 2D API is used to declare paths that are then
+// later used on CanvasRenderingContext2D objects. The path methods of the
+// CanvasRenderingContext2D interface are present on this interface as well,
+// allowing you to create paths that you can retain and replay as required on a canvas.
+type Path2D struct {
+	*js.Object
+}
+
+// NewPath2D creates a new Path2D object.
+// If svgPath is given it is parsed as SVG path data, e.g. "M10 10 h 80 v 80 h -80 Z".
+func NewPath2D(svgPath ...string) *Path2D {
+	ctor := js.Global.Get("Path2D")
+	if len(svgPath) == 0 {
+		return &Path2D{ctor.New()}
+	}
+	return &Path2D{ctor.New(svgPath[0])}
+}
+
+// AddPath Adds to the path the path given by the argument.
+func (p *Path2D) AddPath(other *Path2D) {
+	p.Call("addPath", other.Object)
+}
+
+// ClosePath Causes the point of the pen to move back to the start of the current sub-path.
+func (p *Path2D) ClosePath() {
+	p.Call("closePath")
+}
+
+// MoveTo Moves the starting point of a new sub-path to the (x, y) coordinates.
+func (p *Path2D) MoveTo(x, y float64) {
+	p.Call("moveTo", x, y)
+}
+
+// LineTo Connects the last point in the subpath to the (x, y) coordinates with a straight line.
+func (p *Path2D) LineTo(x, y float64) {
+	p.Call("lineTo", x, y)
+}
+
+// BezierCurveTo Adds a cubic Bézier curve to the path.
+func (p *Path2D) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) {
+	p.Call("bezierCurveTo", cp1x, cp1y, cp2x, cp2y, x, y)
+}
+
+// QuadraticCurveTo Adds a quadratic Bézier curve to the path.
+func (p *Path2D) QuadraticCurveTo(cpx, cpy, x, y float64) {
+	p.Call("quadraticCurveTo", cpx, cpy, x, y)
+}
+
+// Arc Adds an arc to the path which is centered at (x, y) position with radius r
+// starting at startAngle and ending at endAngle, clockwise unless counterclockwise is true.
+func (p *Path2D) Arc(x, y, radius, sAngle, eAngle float64, counterclockwise ...bool) {
+	p.Call("arc", x, y, radius, sAngle, eAngle, len(counterclockwise) > 0 && counterclockwise[0])
+}
+
+// ArcTo Adds an arc to the path with the given control points and radius.
+func (p *Path2D) ArcTo(x1, y1, x2, y2, r float64) {
+	p.Call("arcTo", x1, y1, x2, y2, r)
+}
+
+// Rect Creates a path for a rectangle at position (x, y) with a size that is determined by width and height.
+func (p *Path2D) Rect(x, y, width, height float64) {
+	p.Call("rect", x, y, width, height)
+}
+
+// FillPath Fills the given path with the current fill style using the non-zero winding rule.
+func (ctx *Context2D) FillPath(p *Path2D) {
+	ctx.Call("fill", p.Object)
+}
+
+// StrokePath Strokes the given path with the current stroke style.
+func (ctx *Context2D) StrokePath(p *Path2D) {
+	ctx.Call("stroke", p.Object)
+}
+
+// ClipPath Turns the given path into the current clipping region.
+func (ctx *Context2D) ClipPath(p *Path2D) {
+	ctx.Call("clip", p.Object)
+}
+
+// IsPointInPath2D Reports whether or not the specified point is contained in the given path.
+func (ctx *Context2D) IsPointInPath2D(p *Path2D, x, y float64) bool {
+	return ctx.Call("isPointInPath", p.Object, x, y).Bool()
+}
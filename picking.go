@@ -0,0 +1,145 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// Filler is implemented by shapes that know how to fill themselves onto a context.
+type Filler interface {
+	FillOn(ctx *Context2D)
+}
+
+// FillOn fills r with the current fill style.
+func (r Rect) FillOn(ctx *Context2D) {
+	ctx.FillRect(r.X, r.Y, r.W, r.H)
+}
+
+// FillOn fills c with the current fill style.
+func (c Circle) FillOn(ctx *Context2D) {
+	ctx.FillCircle(c.X, c.Y, c.R)
+}
+
+// FillOn fills poly with the current fill style.
+func (poly Polygon) FillOn(ctx *Context2D) {
+	if len(poly) == 0 {
+		return
+	}
+	ctx.BeginPath()
+	ctx.MoveTo(poly[0].X, poly[0].Y)
+	for _, p := range poly[1:] {
+		ctx.LineTo(p.X, p.Y)
+	}
+	ctx.ClosePath()
+	ctx.Call("fill", "evenodd")
+}
+
+// FillOn fills the path with the current fill style.
+func (s PathShape) FillOn(ctx *Context2D) {
+	ctx.FillPath(s.Path)
+}
+
+// PickBuffer identifies shapes under a point by drawing each registered shape in a unique
+// solid color onto a hidden canvas and reading back a single pixel.
+// For scenes with many shapes this is much cheaper than geometric hit testing.
+//
+// Anti-aliased edges blend neighbouring key colors, so picks within a pixel of a
+// shape boundary may report the wrong shape or none at all.
+type PickBuffer struct {
+	canvas *Canvas
+	ctx    *Context2D
+	ids    []string
+	draws  []func(ctx *Context2D)
+	dirty  bool
+}
+
+// maxPickShapes is the number of distinct keys representable in 24-bit RGB, minus the background.
+const maxPickShapes = 1<<24 - 1
+
+// NewPickBuffer creates a PickBuffer whose hidden canvas has the given size.
+// It should match the size of the visible canvas the shapes are drawn on.
+func NewPickBuffer(width, height int) *PickBuffer {
+	c := Create(width, height)
+	ctx := c.GetContext2D(WithContextAttributes(js.M{"willReadFrequently": true}))
+	return &PickBuffer{canvas: c, ctx: ctx}
+}
+
+// Context returns the context of the hidden canvas, e.g. to apply the same transform as the visible canvas.
+// Call Invalidate after changing it.
+func (p *PickBuffer) Context() *Context2D {
+	return p.ctx
+}
+
+// Resize changes the size of the hidden canvas.
+func (p *PickBuffer) Resize(width, height int) {
+	p.canvas.Set("width", width)
+	p.canvas.Set("height", height)
+	p.dirty = true
+}
+
+// Add registers a shape that fills itself.
+func (p *PickBuffer) Add(id string, s Filler) {
+	p.AddFunc(id, s.FillOn)
+}
+
+// AddFunc registers a shape drawn by draw. Before draw is called the fill and stroke styles
+// are set to the shape's key color; draw must not change them.
+// Shapes added later are on top. AddFunc panics if more than 2^24-1 shapes are registered.
+func (p *PickBuffer) AddFunc(id string, draw func(ctx *Context2D)) {
+	if len(p.ids) >= maxPickShapes {
+		panic("canvas: too many shapes in PickBuffer")
+	}
+	p.ids = append(p.ids, id)
+	p.draws = append(p.draws, draw)
+	p.dirty = true
+}
+
+// Clear unregisters all shapes.
+func (p *PickBuffer) Clear() {
+	p.ids = p.ids[:0]
+	p.draws = p.draws[:0]
+	p.dirty = true
+}
+
+// Invalidate forces the hidden canvas to be redrawn on the next pick,
+// e.g. after the shapes moved.
+func (p *PickBuffer) Invalidate() {
+	p.dirty = true
+}
+
+// PickAt returns the id of the topmost shape at canvas pixel (x, y).
+func (p *PickBuffer) PickAt(x, y int) (id string, ok bool) {
+	if p.dirty {
+		p.redraw()
+	}
+	px := p.ctx.Call("getImageData", x, y, 1, 1).Get("data")
+	if px.Index(3).Int() != 0xff {
+		return "", false
+	}
+	key := px.Index(0).Int()<<16 | px.Index(1).Int()<<8 | px.Index(2).Int()
+	if key == 0 || key > len(p.ids) {
+		return "", false
+	}
+	return p.ids[key-1], true
+}
+
+func (p *PickBuffer) redraw() {
+	ctx := p.ctx
+	ctx.Clear()
+	for i, draw := range p.draws {
+		key := i + 1
+		style := "#" + hex6(key)
+		ctx.FillStyle = style
+		ctx.StrokeStyle = style
+		draw(ctx)
+	}
+	p.dirty = false
+}
+
+// hex6 formats v as six lowercase hex digits.
+func hex6(v int) string {
+	const digits = "0123456789abcdef"
+	var b [6]byte
+	for i := 5; i >= 0; i-- {
+		b[i] = digits[v&0xf]
+		v >>= 4
+	}
+	return string(b[:])
+}
@@ -0,0 +1,52 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// EventPoint converts the clientX/clientY position of a mouse, pointer or touch event
+// into the coordinate space of the canvas, accounting for its position on the page
+// and for any CSS scaling of the element. With WithHiDPI the result is in CSS pixels,
+// matching the scaled context transform.
+func (c *Canvas) EventPoint(ev *js.Object) Point {
+	r := c.Call("getBoundingClientRect")
+	x := ev.Get("clientX").Float() - r.Get("left").Float()
+	y := ev.Get("clientY").Float() - r.Get("top").Float()
+	if w := r.Get("width").Float(); w > 0 {
+		x *= c.Get("width").Float() / c.PixelRatio() / w
+	}
+	if h := r.Get("height").Float(); h > 0 {
+		y *= c.Get("height").Float() / c.PixelRatio() / h
+	}
+	return Point{X: x, Y: y}
+}
+
+// OnPointer registers fn as a listener for the pointer or mouse event typ
+// ("pointerdown", "pointermove", "click", ...) on the canvas.
+// fn receives the event position in canvas coordinates along with the raw event.
+// The returned function removes the listener.
+func (c *Canvas) OnPointer(typ string, fn func(p Point, ev *js.Object)) (remove func()) {
+	listener := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		fn(c.EventPoint(args[0]), args[0])
+		return nil
+	})
+	c.Call("addEventListener", typ, listener)
+	return func() {
+		c.Call("removeEventListener", typ, listener)
+	}
+}
+
+// onPointerDrag registers down, move and up for pointerdown, pointermove and pointerup,
+// with up also receiving pointercancel, as the drag based tools need.
+// The returned function removes all four listeners.
+func (c *Canvas) onPointerDrag(down, move, up func(p Point, ev *js.Object)) (remove func()) {
+	removers := []func(){
+		c.OnPointer("pointerdown", down),
+		c.OnPointer("pointermove", move),
+		c.OnPointer("pointerup", up),
+		c.OnPointer("pointercancel", up),
+	}
+	return func() {
+		for _, r := range removers {
+			r()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// CanvasPool hands out temporary offscreen canvases and takes them back for reuse,
+// avoiding the churn of creating throwaway canvas elements for masking, caching and measurement.
+type CanvasPool struct {
+	// MaxIdle bounds the number of canvases kept for reuse; 0 means 16.
+	MaxIdle int
+	// MaxWaste bounds how many times larger than requested a reused canvas may be; 0 means 4.
+	MaxWaste float64
+
+	idle []*Canvas
+}
+
+// NewCanvasPool creates an empty pool.
+func NewCanvasPool() *CanvasPool {
+	return &CanvasPool{}
+}
+
+// Get returns a cleared canvas at least width x height pixels large with a reset context state.
+// The smallest idle canvas that fits is reused, otherwise a new one of exactly that size is created.
+// Callers that need the exact size should use the rectangle (0, 0, width, height) of it.
+func (p *CanvasPool) Get(width, height int) *Canvas {
+	maxWaste := p.MaxWaste
+	if maxWaste <= 0 {
+		maxWaste = 4
+	}
+	want := float64(width * height)
+	best := -1
+	var bestArea float64
+	for i, c := range p.idle {
+		w, h := c.Size()
+		area := float64(w * h)
+		if w < width || h < height || area > want*maxWaste {
+			continue
+		}
+		if best < 0 || area < bestArea {
+			best, bestArea = i, area
+		}
+	}
+	if best < 0 {
+		return Create(width, height)
+	}
+	c := p.idle[best]
+	p.idle = append(p.idle[:best], p.idle[best+1:]...)
+	// reset clears the canvas and drops the clip and saved states of the previous
+	// user; assigning the width does the same where reset is missing
+	if ctx := c.GetContext2D(); ctx.Get("reset") != js.Undefined {
+		ctx.Call("reset")
+	} else {
+		c.Set("width", c.Get("width"))
+	}
+	c.applyPixelRatio()
+	return c
+}
+
+// Put returns c to the pool. c must not be used afterwards.
+func (p *CanvasPool) Put(c *Canvas) {
+	max := p.MaxIdle
+	if max <= 0 {
+		max = 16
+	}
+	if len(p.idle) >= max {
+		return
+	}
+	p.idle = append(p.idle, c)
+}
+
+// Len returns the number of idle canvases in the pool.
+func (p *CanvasPool) Len() int {
+	return len(p.idle)
+}
+
+// Drain drops all idle canvases, releasing their memory to the browser.
+func (p *CanvasPool) Drain() {
+	for _, c := range p.idle {
+		c.Set("width", 0)
+		c.Set("height", 0)
+	}
+	p.idle = nil
+}
@@ -0,0 +1,41 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// GetImageDataInto reads the pixels of the rectangle x, y, width, height into dst
+// and returns it, so per-frame readback can keep using the same ImageData and the
+// slice returned by its View. If dst is nil or has a different size a new
+// ImageData is returned instead; keep the returned value for the next call.
+//
+// The 2D API has no way to read pixels into an existing buffer, so the browser still
+// creates a short-lived ImageData on every call; it is copied into dst in one
+// call and becomes garbage immediately. Pair this with
+// WithContextAttributes(js.M{"willReadFrequently": true}) to keep readback fast.
+func (ctx *Context2D) GetImageDataInto(dst *ImageData, x, y, width, height int) *ImageData {
+	src := ctx.Call("getImageData", x, y, width, height)
+	if dst == nil || dst.Width != width || dst.Height != height {
+		return &ImageData{Object: src}
+	}
+	dst.Data.Call("set", src.Get("data"))
+	return dst
+}
+
+// View returns the pixel data as a byte slice sharing memory with the ImageData:
+// nothing is copied, and writes to the slice change the ImageData directly.
+// Unlike Bytes, repeated calls don't allocate a new buffer.
+func (i *ImageData) View() []byte {
+	d := i.Data
+	return js.Global.Get("Uint8Array").New(d.Get("buffer"), d.Get("byteOffset"), d.Get("byteLength")).Interface().([]byte)
+}
+
+// BytesInto copies the pixel data into dst, growing it only if its capacity is too
+// small, and returns the filled slice.
+func (i *ImageData) BytesInto(dst []byte) []byte {
+	n := i.Data.Length()
+	if cap(dst) < n {
+		dst = make([]byte, n)
+	}
+	dst = dst[:n]
+	copy(dst, i.View())
+	return dst
+}
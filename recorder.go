@@ -0,0 +1,150 @@
+package canvas
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// CaptureStream The HTMLCanvasElement.captureStream() method returns a MediaStream
+// which includes a CanvasCaptureMediaStreamTrack containing a real-time video capture of the canvas's contents.
+// frameRate is optional; if omitted a new frame is captured each time the canvas changes.
+func (c *Canvas) CaptureStream(frameRate ...float64) *js.Object {
+	if len(frameRate) == 0 {
+		return c.Call("captureStream")
+	}
+	return c.Call("captureStream", frameRate[0])
+}
+
+// RecorderOptions configures a Recorder. Zero values leave the choice to the browser.
+type RecorderOptions struct {
+	// MimeType of the recording, e.g. "video/webm;codecs=vp9".
+	MimeType string
+	// VideoBitsPerSecond is the target video bitrate.
+	VideoBitsPerSecond int
+	// FrameRate passed to captureStream.
+	FrameRate float64
+	// TimeSlice, if positive, makes the browser hand over data every TimeSlice milliseconds
+	// instead of only when stopping, which bounds memory use for long recordings.
+	TimeSlice int
+}
+
+// Recorder records the contents of a canvas to a video using MediaRecorder.
+type Recorder struct {
+	mr     *js.Object
+	chunks []*js.Object
+	opts   RecorderOptions
+	done   func(data []byte, err error)
+}
+
+// ErrRecorderUnsupported is returned when the browser lacks MediaRecorder or the requested mime type.
+var ErrRecorderUnsupported = errors.New("canvas: MediaRecorder or mime type not supported")
+
+// NewRecorder prepares a recording of c. opts may be nil.
+func NewRecorder(c *Canvas, opts *RecorderOptions) (*Recorder, error) {
+	if opts == nil {
+		opts = &RecorderOptions{}
+	}
+	ctor := js.Global.Get("MediaRecorder")
+	if ctor == js.Undefined {
+		return nil, ErrRecorderUnsupported
+	}
+	if opts.MimeType != "" && !ctor.Call("isTypeSupported", opts.MimeType).Bool() {
+		return nil, ErrRecorderUnsupported
+	}
+	var stream *js.Object
+	if opts.FrameRate > 0 {
+		stream = c.CaptureStream(opts.FrameRate)
+	} else {
+		stream = c.CaptureStream()
+	}
+	mrOpts := js.M{}
+	if opts.MimeType != "" {
+		mrOpts["mimeType"] = opts.MimeType
+	}
+	if opts.VideoBitsPerSecond > 0 {
+		mrOpts["videoBitsPerSecond"] = opts.VideoBitsPerSecond
+	}
+	r := &Recorder{mr: ctor.New(stream, mrOpts), opts: *opts}
+	r.mr.Set("ondataavailable", func(ev *js.Object) {
+		if d := ev.Get("data"); d.Get("size").Int() > 0 {
+			r.chunks = append(r.chunks, d)
+		}
+	})
+	r.mr.Set("onstop", func() {
+		r.finish()
+	})
+	r.mr.Set("onerror", func(ev *js.Object) {
+		if r.done != nil {
+			done := r.done
+			r.done = nil
+			done(nil, errors.New("canvas: recording failed: "+ev.Get("error").String()))
+		}
+	})
+	return r, nil
+}
+
+// MimeType returns the mime type the browser actually records in.
+func (r *Recorder) MimeType() string {
+	return r.mr.Get("mimeType").String()
+}
+
+// State returns "inactive", "recording" or "paused".
+func (r *Recorder) State() string {
+	return r.mr.Get("state").String()
+}
+
+// Start begins recording, discarding any previously recorded data.
+func (r *Recorder) Start() {
+	r.chunks = nil
+	if r.opts.TimeSlice > 0 {
+		r.mr.Call("start", r.opts.TimeSlice)
+		return
+	}
+	r.mr.Call("start")
+}
+
+// Pause pauses the recording.
+func (r *Recorder) Pause() {
+	r.mr.Call("pause")
+}
+
+// Resume continues a paused recording.
+func (r *Recorder) Resume() {
+	r.mr.Call("resume")
+}
+
+// Stop ends the recording. done is called asynchronously with the encoded video,
+// e.g. WebM bytes, once the browser has flushed all data.
+func (r *Recorder) Stop(done func(data []byte, err error)) {
+	r.done = done
+	r.mr.Call("stop")
+}
+
+func (r *Recorder) finish() {
+	done := r.done
+	r.done = nil
+	if done == nil {
+		return
+	}
+	parts := make([]interface{}, len(r.chunks))
+	for i, c := range r.chunks {
+		parts[i] = c
+	}
+	r.chunks = nil
+	blob := js.Global.Get("Blob").New(parts, js.M{"type": r.MimeType()})
+	readBlob(blob, done)
+}
+
+// readBlob reads the contents of a Blob into a Go byte slice asynchronously.
+func readBlob(blob *js.Object, done func([]byte, error)) {
+	reader := js.Global.Get("FileReader").New()
+	reader.Set("onload", func() {
+		buf := reader.Get("result")
+		done(js.Global.Get("Uint8Array").New(buf).Interface().([]byte), nil)
+	})
+	reader.Set("onerror", func() {
+		done(nil, errors.New("canvas: reading blob failed: "+reader.Get("error").String()))
+	})
+	reader.Call("readAsArrayBuffer", blob)
+}
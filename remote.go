@@ -0,0 +1,123 @@
+package canvas
+
+import (
+	"encoding/json"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// RemoteSender streams drawing commands to a RemoteClient over a WebSocket, a
+// MessagePort, a Worker or anything else with a send or postMessage method. Each
+// message carries one CommandLog, in the binary form of MarshalBinary or as JSON.
+type RemoteSender struct {
+	// JSON sends text messages instead of binary ones, for transports or servers
+	// that only relay text.
+	JSON bool
+
+	target *js.Object
+}
+
+// NewRemoteSender creates a sender posting to target.
+func NewRemoteSender(target *js.Object) *RemoteSender {
+	return &RemoteSender{target: target}
+}
+
+// Send posts log as one message.
+func (s *RemoteSender) Send(log CommandLog) error {
+	var msg interface{}
+	if s.JSON {
+		b, err := json.Marshal(log)
+		if err != nil {
+			return err
+		}
+		msg = string(b)
+	} else {
+		b, err := log.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		msg = b
+	}
+	if s.target.Get("send") != js.Undefined {
+		s.target.Call("send", msg)
+	} else {
+		s.target.Call("postMessage", msg)
+	}
+	return nil
+}
+
+// Flush sends the commands recorded by rec since its last reset and resets it.
+// Drawing on a RecordingContext whose Target is the local canvas and flushing
+// once per frame mirrors the local drawing to the remote side.
+func (s *RemoteSender) Flush(rec *RecordingContext) error {
+	if len(rec.Log) == 0 {
+		return nil
+	}
+	err := s.Send(rec.Log)
+	rec.Reset()
+	return err
+}
+
+// RemoteClient replays the command messages arriving from a RemoteSender onto a
+// local context.
+type RemoteClient struct {
+	// OnError is called with messages that fail to decode, contain commands a
+	// RecordingContext cannot produce or make the browser throw while replaying.
+	// They are dropped otherwise.
+	OnError func(err error)
+	// OnCommands, if set, is called after each message has been replayed, e.g. to
+	// redraw overlays.
+	OnCommands func(log CommandLog)
+
+	source   *js.Object
+	ctx      *Context2D
+	listener *js.Object
+}
+
+// NewRemoteClient listens for messages on source, a WebSocket, MessagePort or
+// Worker, and replays them onto ctx. A WebSocket is switched to binary messages as
+// ArrayBuffers. A MessagePort is started.
+func NewRemoteClient(source *js.Object, ctx *Context2D) *RemoteClient {
+	c := &RemoteClient{source: source, ctx: ctx}
+	if source.Get("binaryType") != js.Undefined {
+		source.Set("binaryType", "arraybuffer")
+	}
+	c.listener = js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		c.receive(args[0].Get("data"))
+		return nil
+	})
+	source.Call("addEventListener", "message", c.listener)
+	if source.Get("start") != js.Undefined {
+		source.Call("start")
+	}
+	return c
+}
+
+// Close stops listening. The source itself is left open.
+func (c *RemoteClient) Close() {
+	c.source.Call("removeEventListener", "message", c.listener)
+}
+
+func (c *RemoteClient) receive(data *js.Object) {
+	var log CommandLog
+	var err error
+	if data.Get("constructor") == js.Global.Get("String") {
+		err = json.Unmarshal([]byte(data.String()), &log)
+	} else {
+		// ArrayBuffer, or a typed array from postMessage
+		b := js.Global.Get("Uint8Array").New(data).Interface().([]byte)
+		err = log.UnmarshalBinary(b)
+	}
+	if err == nil {
+		err = log.ReplayChecked(c.ctx)
+	}
+	if err != nil {
+		if c.OnError != nil {
+			c.OnError(err)
+		}
+		return
+	}
+	if c.OnCommands != nil {
+		c.OnCommands(log)
+	}
+}
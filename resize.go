@@ -0,0 +1,76 @@
+package canvas
+
+// ScaleMode says how ResizePreservingContent places the old contents in the resized canvas.
+type ScaleMode int
+
+const (
+	// ScaleAnchorTopLeft keeps the contents at their size in the top left corner,
+	// cropping them if the canvas shrinks.
+	ScaleAnchorTopLeft ScaleMode = iota
+	// ScaleAnchorCenter keeps the contents at their size, centered.
+	ScaleAnchorCenter
+	// ScaleStretch scales the contents to the new size, distorting them if the aspect ratio changes.
+	ScaleStretch
+	// ScaleFit scales the contents to the largest size fitting the canvas while
+	// keeping their aspect ratio, centered.
+	ScaleFit
+)
+
+// ResizePreservingContent sets the size of the canvas in CSS pixels like SetSize, but
+// redraws the previous contents afterwards, placed according to mode, instead of
+// leaving the canvas cleared. Like SetSize it resets the context state.
+func (c *Canvas) ResizePreservingContent(width, height int, mode ScaleMode) {
+	snap := c.snapshot()
+	c.SetSize(width, height)
+	c.restoreSnapshot(snap, mode)
+}
+
+// WithPreserveContent makes WithAutoResize redraw the previous contents after every
+// resize, placed according to mode, instead of clearing the canvas.
+func WithPreserveContent(mode ScaleMode) Option {
+	return func(o *options) {
+		o.preserve = true
+		o.preserveMode = mode
+	}
+}
+
+func (c *Canvas) snapshot() *Canvas {
+	w, h := c.Size()
+	if w == 0 || h == 0 {
+		return nil
+	}
+	return copyArea(c, Rect{W: float64(w), H: float64(h)})
+}
+
+// restoreSnapshot draws snap, taken by snapshot, into the canvas and releases it.
+func (c *Canvas) restoreSnapshot(snap *Canvas, mode ScaleMode) {
+	if snap == nil {
+		return
+	}
+	sw, sh := snap.Size()
+	w, h := c.Size()
+	fw, fh, tw, th := float64(sw), float64(sh), float64(w), float64(h)
+	x, y := 0.0, 0.0
+	switch mode {
+	case ScaleAnchorCenter:
+		x, y = (tw-fw)/2, (th-fh)/2
+	case ScaleStretch:
+		fw, fh = tw, th
+	case ScaleFit:
+		s := tw / fw
+		if th/fh < s {
+			s = th / fh
+		}
+		fw, fh = fw*s, fh*s
+		x, y = (tw-fw)/2, (th-fh)/2
+	}
+	ctx := c.GetContext2D()
+	ctx.Save()
+	ctx.SetTransform(1, 0, 0, 1, 0, 0)
+	ctx.DrawImage(snap, x, y, fw, fh)
+	ctx.Restore()
+	// release the backing store now instead of waiting for the garbage collector,
+	// auto-resize takes a snapshot on every observer callback
+	snap.Set("width", 0)
+	snap.Set("height", 0)
+}
@@ -0,0 +1,305 @@
+package canvas
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Guide is a horizontal or vertical guide line at a position in world units.
+type Guide struct {
+	Vertical bool
+	Pos      float64
+}
+
+// Rulers draws horizontal and vertical rulers along the top and left edges of an
+// overlay canvas and manages guides that are dragged out of them.
+//
+// Positions on the overlay, in CSS pixels, map to world units as
+// world = (css - Origin) / Scale, so the rulers can follow a pan/zoom view by
+// updating Origin and Scale and calling Draw.
+type Rulers struct {
+	// Thickness of the rulers in CSS pixels, 20 by default.
+	Thickness float64
+	// Origin is the position of the world origin on the overlay in CSS pixels.
+	Origin Point
+	// Scale is the number of CSS pixels per world unit, 1 by default.
+	Scale float64
+	// Guides are the current guides.
+	Guides []Guide
+	// SnapDistance is the distance in CSS pixels within which Snap pulls a point onto
+	// a guide, 6 by default.
+	SnapDistance float64
+	// Background, Color and GuideColor style the rulers, their ticks and the guides.
+	Background, Color, GuideColor string
+	// OnChange is called whenever a guide is added, moved or removed.
+	OnChange func(guides []Guide)
+	// Underlay is called before each redraw to repaint what lies below the rulers.
+	// If nil the whole overlay is cleared.
+	Underlay func(ctx *Context2D)
+
+	canvas *Canvas
+	ctx    *Context2D
+	remove func()
+	drag   int
+}
+
+// NewRulers creates rulers on the overlay canvas c, which should be stacked above the
+// content and receive its pointer events. Call Detach to stop listening.
+func NewRulers(c *Canvas) *Rulers {
+	r := &Rulers{
+		Thickness:    20,
+		Scale:        1,
+		SnapDistance: 6,
+		Background:   "#f4f4f4",
+		Color:        "#555",
+		GuideColor:   "#00bcd4",
+		canvas:       c,
+		ctx:          c.GetContext2D(),
+		drag:         -1,
+	}
+	r.remove = c.onPointerDrag(r.down, r.move, r.up)
+	r.Draw()
+	return r
+}
+
+// Detach removes the event listeners.
+func (r *Rulers) Detach() {
+	r.remove()
+}
+
+// validScale reports whether Scale is positive and finite.
+func (r *Rulers) validScale() bool {
+	return r.Scale > 0 && !math.IsInf(r.Scale, 1)
+}
+
+// scale returns Scale, or 1 if it is not positive and finite.
+func (r *Rulers) scale() float64 {
+	if !r.validScale() {
+		return 1
+	}
+	return r.Scale
+}
+
+// ToWorld converts a point on the overlay in CSS pixels to world units.
+func (r *Rulers) ToWorld(p Point) Point {
+	s := r.scale()
+	return Point{X: (p.X - r.Origin.X) / s, Y: (p.Y - r.Origin.Y) / s}
+}
+
+// FromWorld converts a point in world units to CSS pixels on the overlay.
+func (r *Rulers) FromWorld(p Point) Point {
+	s := r.scale()
+	return Point{X: p.X*s + r.Origin.X, Y: p.Y*s + r.Origin.Y}
+}
+
+// Snap moves the world point p onto the nearest vertical and horizontal guide within
+// SnapDistance, and reports whether it snapped at all.
+func (r *Rulers) Snap(p Point) (Point, bool) {
+	snapped := false
+	bestX, bestY := r.SnapDistance/r.scale(), r.SnapDistance/r.scale()
+	q := p
+	for _, g := range r.Guides {
+		if g.Vertical {
+			if d := math.Abs(p.X - g.Pos); d <= bestX {
+				bestX, q.X, snapped = d, g.Pos, true
+			}
+		} else if d := math.Abs(p.Y - g.Pos); d <= bestY {
+			bestY, q.Y, snapped = d, g.Pos, true
+		}
+	}
+	return q, snapped
+}
+
+// Draw repaints the rulers and guides. It draws nothing while Scale is not positive
+// and finite or Origin is not finite, since no ticks can be placed.
+func (r *Rulers) Draw() {
+	o := r.Origin
+	if !r.validScale() || math.IsNaN(o.X+o.Y) || math.IsInf(o.X+o.Y, 0) {
+		return
+	}
+	ctx := r.ctx
+	if r.Underlay != nil {
+		r.Underlay(ctx)
+	} else {
+		ctx.Clear()
+	}
+	w, h := r.cssSize()
+	t := r.Thickness
+
+	ctx.Save()
+	ctx.LineWidth = 1
+	ctx.StrokeStyle = r.GuideColor
+	ctx.BeginPath()
+	for _, g := range r.Guides {
+		p := r.FromWorld(Point{X: g.Pos, Y: g.Pos})
+		if g.Vertical {
+			x := math.Floor(p.X) + 0.5
+			ctx.MoveTo(x, t)
+			ctx.LineTo(x, h)
+		} else {
+			y := math.Floor(p.Y) + 0.5
+			ctx.MoveTo(t, y)
+			ctx.LineTo(w, y)
+		}
+	}
+	ctx.Stroke()
+
+	ctx.FillStyle = r.Background
+	ctx.FillRect(0, 0, w, t)
+	ctx.FillRect(0, 0, t, h)
+	ctx.StrokeStyle = r.Color
+	ctx.FillStyle = r.Color
+	ctx.Font = "10px sans-serif"
+	ctx.SetTextBaseline(TextBaselineTop)
+	ctx.BeginPath()
+	ctx.MoveTo(t, t-0.5)
+	ctx.LineTo(w, t-0.5)
+	ctx.MoveTo(t-0.5, t)
+	ctx.LineTo(t-0.5, h)
+
+	step := niceStep(50 / r.scale())
+	minor := step / 5
+	for v := math.Floor(r.ToWorld(Point{X: t}).X/minor) * minor; ; v += minor {
+		x := r.FromWorld(Point{X: v}).X
+		if x > w {
+			break
+		}
+		if x < t {
+			continue
+		}
+		x = math.Floor(x) + 0.5
+		major := isMultiple(v, step)
+		ctx.MoveTo(x, t)
+		if major {
+			ctx.LineTo(x, 0)
+			ctx.FillText(formatTick(v), x+2, 1)
+		} else {
+			ctx.LineTo(x, t*0.7)
+		}
+	}
+	for v := math.Floor(r.ToWorld(Point{Y: t}).Y/minor) * minor; ; v += minor {
+		y := r.FromWorld(Point{Y: v}).Y
+		if y > h {
+			break
+		}
+		if y < t {
+			continue
+		}
+		y = math.Floor(y) + 0.5
+		major := isMultiple(v, step)
+		ctx.MoveTo(t, y)
+		if major {
+			ctx.LineTo(0, y)
+			ctx.Save()
+			ctx.Translate(1, y-2)
+			ctx.Rotate(-math.Pi / 2)
+			ctx.FillText(formatTick(v), 0, 0)
+			ctx.Restore()
+		} else {
+			ctx.LineTo(t*0.7, y)
+		}
+	}
+	ctx.Stroke()
+	ctx.Restore()
+}
+
+func (r *Rulers) cssSize() (w, h float64) {
+	pw, ph := r.canvas.Size()
+	ratio := r.canvas.PixelRatio()
+	return float64(pw) / ratio, float64(ph) / ratio
+}
+
+// guideAt returns the index of the guide under p, or -1.
+func (r *Rulers) guideAt(p Point) int {
+	for i, g := range r.Guides {
+		q := r.FromWorld(Point{X: g.Pos, Y: g.Pos})
+		if g.Vertical && math.Abs(q.X-p.X) <= 3 || !g.Vertical && math.Abs(q.Y-p.Y) <= 3 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Rulers) down(p Point, ev *js.Object) {
+	t := r.Thickness
+	switch {
+	case p.Y < t && p.X > t:
+		r.Guides = append(r.Guides, Guide{Vertical: false, Pos: r.ToWorld(p).Y})
+		r.drag = len(r.Guides) - 1
+	case p.X < t && p.Y > t:
+		r.Guides = append(r.Guides, Guide{Vertical: true, Pos: r.ToWorld(p).X})
+		r.drag = len(r.Guides) - 1
+	default:
+		r.drag = r.guideAt(p)
+	}
+	if r.drag >= 0 {
+		r.canvas.Call("setPointerCapture", ev.Get("pointerId"))
+		r.changed()
+	}
+}
+
+func (r *Rulers) move(p Point, ev *js.Object) {
+	if r.drag < 0 {
+		cursor := ""
+		if i := r.guideAt(p); i >= 0 {
+			cursor = "row-resize"
+			if r.Guides[i].Vertical {
+				cursor = "col-resize"
+			}
+		}
+		r.canvas.Get("style").Set("cursor", cursor)
+		return
+	}
+	g := &r.Guides[r.drag]
+	if g.Vertical {
+		g.Pos = r.ToWorld(p).X
+	} else {
+		g.Pos = r.ToWorld(p).Y
+	}
+	r.changed()
+}
+
+func (r *Rulers) up(p Point, ev *js.Object) {
+	if r.drag < 0 {
+		return
+	}
+	// dropping a guide back onto its ruler removes it
+	g := r.Guides[r.drag]
+	if g.Vertical && p.X < r.Thickness || !g.Vertical && p.Y < r.Thickness {
+		r.Guides = append(r.Guides[:r.drag], r.Guides[r.drag+1:]...)
+	}
+	r.drag = -1
+	r.changed()
+}
+
+func (r *Rulers) changed() {
+	r.Draw()
+	if r.OnChange != nil {
+		r.OnChange(r.Guides)
+	}
+}
+
+// niceStep rounds v up to 1, 2 or 5 times a power of ten.
+func niceStep(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	p := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*p >= v {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+func isMultiple(v, step float64) bool {
+	q := v / step
+	return math.Abs(q-math.Round(q)) < 1e-6
+}
+
+func formatTick(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
@@ -0,0 +1,291 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Scene is a flat list of shapes forming a document that can be rendered onto a
+// context and saved to and restored from JSON.
+type Scene struct {
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	Background string  `json:"background,omitempty"`
+	Nodes      []*Node `json:"nodes"`
+}
+
+// Node is a shape in a Scene along with its style and transform.
+// It marshals to a JSON object whose "type" field names the shape kind.
+type Node struct {
+	ID        string
+	Style     NodeStyle
+	Transform *[6]float64
+	Shape     SceneShape
+}
+
+// NodeStyle is the serializable paint style of a Node. Colors are CSS color strings.
+type NodeStyle struct {
+	Fill      string    `json:"fill,omitempty"`
+	Stroke    string    `json:"stroke,omitempty"`
+	LineWidth float64   `json:"lineWidth,omitempty"`
+	LineDash  []float64 `json:"lineDash,omitempty"`
+	Opacity   *float64  `json:"opacity,omitempty"`
+	Font      string    `json:"font,omitempty"`
+}
+
+// SceneShape is the geometry of a Node. Shapes are encoded as JSON with
+// encoding/json; custom kinds are added with RegisterSceneShape.
+type SceneShape interface {
+	// Kind is the name stored in the "type" field of the JSON encoding.
+	Kind() string
+	// Draw paints the shape on ctx with the fill and stroke styles already set;
+	// fill and stroke report which of them the node uses.
+	Draw(ctx *Context2D, fill, stroke bool)
+}
+
+var sceneShapes = map[string]func() SceneShape{
+	"rect":     func() SceneShape { return new(RectShape) },
+	"circle":   func() SceneShape { return new(CircleShape) },
+	"polyline": func() SceneShape { return new(PolylineShape) },
+	"path":     func() SceneShape { return new(SVGPathShape) },
+	"text":     func() SceneShape { return new(TextShape) },
+	"image":    func() SceneShape { return new(ImageShape) },
+}
+
+// RegisterSceneShape makes a custom shape kind known to Node.UnmarshalJSON.
+// newShape returns a pointer to a zero shape to decode into.
+func RegisterSceneShape(kind string, newShape func() SceneShape) {
+	sceneShapes[kind] = newShape
+}
+
+type nodeJSON struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id,omitempty"`
+	Style     NodeStyle       `json:"style"`
+	Transform *[6]float64     `json:"transform,omitempty"`
+	Shape     json.RawMessage `json:"shape"`
+}
+
+// MarshalJSON encodes the node as {"type", "id", "style", "transform", "shape"}.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	if n.Shape == nil {
+		return nil, fmt.Errorf("canvas: node %q has no shape", n.ID)
+	}
+	shape, err := json.Marshal(n.Shape)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(nodeJSON{
+		Type:      n.Shape.Kind(),
+		ID:        n.ID,
+		Style:     n.Style,
+		Transform: n.Transform,
+		Shape:     shape,
+	})
+}
+
+// UnmarshalJSON decodes a node produced by MarshalJSON.
+func (n *Node) UnmarshalJSON(b []byte) error {
+	var v nodeJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	newShape, ok := sceneShapes[v.Type]
+	if !ok {
+		return fmt.Errorf("canvas: unknown shape type %q", v.Type)
+	}
+	shape := newShape()
+	if len(v.Shape) > 0 {
+		if err := json.Unmarshal(v.Shape, shape); err != nil {
+			return err
+		}
+	}
+	n.ID, n.Style, n.Transform, n.Shape = v.ID, v.Style, v.Transform, shape
+	return nil
+}
+
+// Find returns the node with the given id, nil if there is none.
+func (s *Scene) Find(id string) *Node {
+	for _, n := range s.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// Render clears ctx, paints the background and draws all nodes in order.
+func (s *Scene) Render(ctx *Context2D) {
+	ctx.Clear()
+	if s.Background != "" {
+		ctx.Save()
+		ctx.FillStyle = s.Background
+		ctx.FillRect(0, 0, s.Width, s.Height)
+		ctx.Restore()
+	}
+	for _, n := range s.Nodes {
+		n.Draw(ctx)
+	}
+}
+
+// Draw paints the node on ctx.
+func (n *Node) Draw(ctx *Context2D) {
+	if n.Shape == nil {
+		return
+	}
+	ctx.Save()
+	defer ctx.Restore()
+	if m := n.Transform; m != nil {
+		ctx.Transform(m[0], m[1], m[2], m[3], m[4], m[5])
+	}
+	st := n.Style
+	if st.Opacity != nil {
+		ctx.GlobalAlpha *= *st.Opacity
+	}
+	if st.Fill != "" {
+		ctx.FillStyle = st.Fill
+	}
+	if st.Stroke != "" {
+		ctx.StrokeStyle = st.Stroke
+	}
+	if st.LineWidth > 0 {
+		ctx.LineWidth = st.LineWidth
+	}
+	if st.LineDash != nil {
+		ctx.SetLineDash(st.LineDash...)
+	}
+	if st.Font != "" {
+		ctx.Font = st.Font
+	}
+	n.Shape.Draw(ctx, st.Fill != "", st.Stroke != "")
+}
+
+func fillStroke(ctx *Context2D, fill, stroke bool) {
+	if fill {
+		ctx.Fill()
+	}
+	if stroke {
+		ctx.Stroke()
+	}
+}
+
+// RectShape is a rectangle, optionally with rounded corners.
+type RectShape struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	W      float64 `json:"w"`
+	H      float64 `json:"h"`
+	Radius float64 `json:"radius,omitempty"`
+}
+
+// Kind implements SceneShape.
+func (r *RectShape) Kind() string { return "rect" }
+
+// Draw implements SceneShape.
+func (r *RectShape) Draw(ctx *Context2D, fill, stroke bool) {
+	ctx.BeginPath()
+	roundRectPath(ctx, r.X, r.Y, r.W, r.H, r.Radius)
+	fillStroke(ctx, fill, stroke)
+}
+
+// CircleShape is a circle.
+type CircleShape struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	R float64 `json:"r"`
+}
+
+// Kind implements SceneShape.
+func (c *CircleShape) Kind() string { return "circle" }
+
+// Draw implements SceneShape.
+func (c *CircleShape) Draw(ctx *Context2D, fill, stroke bool) {
+	ctx.BeginPath()
+	ctx.Arc(c.X, c.Y, c.R, 0, 2*math.Pi)
+	fillStroke(ctx, fill, stroke)
+}
+
+// PolylineShape is an open or closed sequence of points, such as a freehand stroke.
+type PolylineShape struct {
+	Points []Point `json:"points"`
+	Closed bool    `json:"closed,omitempty"`
+}
+
+// Kind implements SceneShape.
+func (p *PolylineShape) Kind() string { return "polyline" }
+
+// Draw implements SceneShape.
+func (p *PolylineShape) Draw(ctx *Context2D, fill, stroke bool) {
+	if len(p.Points) == 0 {
+		return
+	}
+	ctx.BeginPath()
+	tracePolyline(ctx, p.Points)
+	if p.Closed {
+		ctx.ClosePath()
+	}
+	fillStroke(ctx, fill, stroke)
+}
+
+// SVGPathShape is a path given as SVG path data.
+type SVGPathShape struct {
+	D string `json:"d"`
+}
+
+// Kind implements SceneShape.
+func (p *SVGPathShape) Kind() string { return "path" }
+
+// Draw implements SceneShape.
+func (p *SVGPathShape) Draw(ctx *Context2D, fill, stroke bool) {
+	path := NewPath2D(p.D)
+	if fill {
+		ctx.FillPath(path)
+	}
+	if stroke {
+		ctx.StrokePath(path)
+	}
+}
+
+// TextShape is a line of text; the font comes from the node style.
+type TextShape struct {
+	Text string  `json:"text"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// Kind implements SceneShape.
+func (t *TextShape) Kind() string { return "text" }
+
+// Draw implements SceneShape.
+func (t *TextShape) Draw(ctx *Context2D, fill, stroke bool) {
+	if fill || !stroke {
+		ctx.FillText(t.Text, t.X, t.Y)
+	}
+	if stroke {
+		ctx.StrokeText(t.Text, t.X, t.Y)
+	}
+}
+
+// ImageShape is an image referenced by URL. Images are loaded through
+// DefaultImageCache; until an image has loaded nothing is drawn, so redraw the scene
+// from DefaultImageCache.OnLoad.
+type ImageShape struct {
+	URL string  `json:"url"`
+	X   float64 `json:"x"`
+	Y   float64 `json:"y"`
+	W   float64 `json:"w"`
+	H   float64 `json:"h"`
+}
+
+// Kind implements SceneShape.
+func (i *ImageShape) Kind() string { return "image" }
+
+// Draw implements SceneShape.
+func (i *ImageShape) Draw(ctx *Context2D, fill, stroke bool) {
+	img, ok := DefaultImageCache.Image(i.URL)
+	if !ok {
+		return
+	}
+	ctx.DrawImage(img, i.X, i.Y, i.W, i.H)
+}
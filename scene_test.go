@@ -0,0 +1,89 @@
+package canvas
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testStarShape struct {
+	Points int `json:"points"`
+}
+
+func (s *testStarShape) Kind() string                           { return "test-star" }
+func (s *testStarShape) Draw(ctx *Context2D, fill, stroke bool) {}
+
+func TestSceneJSONRoundTrip(t *testing.T) {
+	RegisterSceneShape("test-star", func() SceneShape { return new(testStarShape) })
+	defer delete(sceneShapes, "test-star")
+	opacity := 0.5
+	scene := &Scene{
+		Width:      640,
+		Height:     480,
+		Background: "#fff",
+		Nodes: []*Node{
+			{ID: "bg", Style: NodeStyle{Fill: "red"}, Shape: &RectShape{X: 1, Y: 2, W: 30, H: 40, Radius: 4}},
+			{Style: NodeStyle{Stroke: "blue", LineWidth: 2, LineDash: []float64{4, 2}, Opacity: &opacity}, Shape: &CircleShape{X: 5, Y: 6, R: 7}},
+			{ID: "line", Transform: &[6]float64{1, 0, 0, 1, 10, 20}, Shape: &PolylineShape{Points: []Point{{0, 0}, {3, 4}}, Closed: true}},
+			{Shape: &SVGPathShape{D: "M0 0L10 10Z"}},
+			{Style: NodeStyle{Fill: "black", Font: "12px sans-serif"}, Shape: &TextShape{Text: "hi \"there\"", X: 1, Y: 2}},
+			{Shape: &ImageShape{URL: "a.png", X: 1, Y: 2, W: 3, H: 4}},
+			{ID: "custom", Shape: &testStarShape{Points: 5}},
+		},
+	}
+	b, err := json.Marshal(scene)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got Scene
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(&got, scene) {
+		t.Errorf("round trip of %s\ngot  %+v\nwant %+v", b, got, *scene)
+	}
+	if got.Find("custom") == nil || got.Find("missing") != nil {
+		t.Errorf("Find after round trip did not locate nodes by id")
+	}
+}
+
+func TestNodeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    SceneShape
+		wantErr string
+	}{
+		{"rect", `{"type":"rect","shape":{"x":1,"y":2,"w":3,"h":4}}`, &RectShape{X: 1, Y: 2, W: 3, H: 4}, ""},
+		{"missing shape gives zero shape", `{"type":"circle"}`, &CircleShape{}, ""},
+		{"unknown fields ignored", `{"type":"path","extra":1,"shape":{"d":"M0 0","x":1}}`, &SVGPathShape{D: "M0 0"}, ""},
+		{"unknown type", `{"type":"hexagon","shape":{}}`, nil, `unknown shape type "hexagon"`},
+		{"missing type", `{"shape":{"x":1}}`, nil, `unknown shape type ""`},
+		{"bad shape", `{"type":"rect","shape":{"x":"one"}}`, nil, "cannot unmarshal"},
+		{"not an object", `[1,2]`, nil, "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		var n Node
+		err := json.Unmarshal([]byte(tt.in), &n)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v, want it to contain %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(n.Shape, tt.want) {
+			t.Errorf("%s: shape = %+v, want %+v", tt.name, n.Shape, tt.want)
+		}
+	}
+}
+
+func TestNodeMarshalJSONWithoutShape(t *testing.T) {
+	if _, err := json.Marshal(&Node{ID: "empty"}); err == nil {
+		t.Errorf("Marshal of a node without shape succeeded, want an error")
+	}
+}
@@ -0,0 +1,87 @@
+package canvas
+
+// SelectionOutline strokes a path with an animated dash pattern ("marching ants"),
+// advancing the dash offset on every animation frame.
+// It is meant to be drawn on an overlay canvas above the actual content.
+type SelectionOutline struct {
+	// Path is the outline to animate.
+	Path *Path2D
+	// Dash is the dash pattern, [4, 4] by default.
+	Dash []float64
+	// Speed is the distance the dashes travel per second, 20 by default.
+	Speed float64
+	// Color and Background are the colors of the dashes and the gaps,
+	// black and white by default.
+	Color, Background string
+	// LineWidth of the outline, 1 by default.
+	LineWidth float64
+	// Underlay is called before each frame is drawn to repaint what lies below the outline.
+	// If nil the whole canvas is cleared.
+	Underlay func(ctx *Context2D)
+
+	ctx    *Context2D
+	offset float64
+	ticker *Ticker
+}
+
+// NewSelectionOutline creates a stopped SelectionOutline drawing path onto ctx.
+func NewSelectionOutline(ctx *Context2D, path *Path2D) *SelectionOutline {
+	s := &SelectionOutline{
+		Path:       path,
+		Dash:       []float64{4, 4},
+		Speed:      20,
+		Color:      "#000",
+		Background: "#fff",
+		LineWidth:  1,
+		ctx:        ctx,
+	}
+	s.ticker = NewTicker(s.frame)
+	return s
+}
+
+// Start begins animating the outline.
+func (s *SelectionOutline) Start() {
+	s.ticker.Start()
+}
+
+// Stop stops animating the outline, leaving the last frame on the canvas.
+func (s *SelectionOutline) Stop() {
+	s.ticker.Stop()
+}
+
+// Draw paints the outline once at the current dash offset.
+func (s *SelectionOutline) Draw() {
+	if s.Path == nil {
+		return
+	}
+	ctx := s.ctx
+	ctx.Save()
+	ctx.LineWidth = s.LineWidth
+	ctx.SetLineDash()
+	ctx.StrokeStyle = s.Background
+	ctx.StrokePath(s.Path)
+	ctx.SetLineDash(s.Dash...)
+	ctx.LineDashOffset = -s.offset
+	ctx.StrokeStyle = s.Color
+	ctx.StrokePath(s.Path)
+	ctx.Restore()
+}
+
+func (s *SelectionOutline) frame(f FrameInfo) {
+	s.offset += s.Speed * f.Delta / 1000
+	var period float64
+	for _, d := range s.Dash {
+		period += d
+	}
+	if period > 0 {
+		for s.offset >= period {
+			s.offset -= period
+		}
+	}
+	if s.Underlay != nil {
+		s.Underlay(s.ctx)
+	} else {
+		s.ctx.Clear()
+	}
+	s.Draw()
+}
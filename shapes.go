@@ -0,0 +1,133 @@
+package canvas
+
+import "math"
+
+// Polygon adds the closed polygon through pts to the current path.
+func (ctx *Context2D) Polygon(pts []Point) {
+	if len(pts) == 0 {
+		return
+	}
+	tracePolyline(ctx, pts)
+	ctx.ClosePath()
+}
+
+// RegularPolygon adds a regular polygon with the given number of sides, inscribed in
+// the circle of radius r around (cx, cy), to the current path. With a rotation of 0
+// the first vertex points straight up.
+func (ctx *Context2D) RegularPolygon(cx, cy, r float64, sides int, rotation float64) {
+	if sides < 3 {
+		return
+	}
+	ctx.Polygon(regularPolygon(cx, cy, r, sides, rotation))
+}
+
+// Star adds a star with the given number of points to the current path, alternating
+// between outerR and innerR around (cx, cy), with the first point straight up.
+func (ctx *Context2D) Star(cx, cy, outerR, innerR float64, points int) {
+	if points < 2 {
+		return
+	}
+	pts := make([]Point, 2*points)
+	for i := range pts {
+		r := outerR
+		if i%2 == 1 {
+			r = innerR
+		}
+		a := -math.Pi/2 + float64(i)*math.Pi/float64(points)
+		pts[i] = Point{X: cx + r*math.Cos(a), Y: cy + r*math.Sin(a)}
+	}
+	ctx.Polygon(pts)
+}
+
+// FillPolygon fills the polygon through pts with the current fill style.
+func (ctx *Context2D) FillPolygon(pts []Point) {
+	ctx.BeginPath()
+	ctx.Polygon(pts)
+	ctx.Fill()
+}
+
+// StrokePolygon strokes the closed polygon through pts with the current stroke style.
+func (ctx *Context2D) StrokePolygon(pts []Point) {
+	ctx.BeginPath()
+	ctx.Polygon(pts)
+	ctx.Stroke()
+}
+
+// FillRegularPolygon fills a regular polygon, see RegularPolygon.
+func (ctx *Context2D) FillRegularPolygon(cx, cy, r float64, sides int, rotation float64) {
+	ctx.BeginPath()
+	ctx.RegularPolygon(cx, cy, r, sides, rotation)
+	ctx.Fill()
+}
+
+// StrokeRegularPolygon strokes a regular polygon, see RegularPolygon.
+func (ctx *Context2D) StrokeRegularPolygon(cx, cy, r float64, sides int, rotation float64) {
+	ctx.BeginPath()
+	ctx.RegularPolygon(cx, cy, r, sides, rotation)
+	ctx.Stroke()
+}
+
+// FillStar fills a star, see Star.
+func (ctx *Context2D) FillStar(cx, cy, outerR, innerR float64, points int) {
+	ctx.BeginPath()
+	ctx.Star(cx, cy, outerR, innerR, points)
+	ctx.Fill()
+}
+
+// StrokeStar strokes a star, see Star.
+func (ctx *Context2D) StrokeStar(cx, cy, outerR, innerR float64, points int) {
+	ctx.BeginPath()
+	ctx.Star(cx, cy, outerR, innerR, points)
+	ctx.Stroke()
+}
+
+func regularPolygon(cx, cy, r float64, sides int, rotation float64) []Point {
+	pts := make([]Point, sides)
+	for i := range pts {
+		a := rotation - math.Pi/2 + 2*math.Pi*float64(i)/float64(sides)
+		pts[i] = Point{X: cx + r*math.Cos(a), Y: cy + r*math.Sin(a)}
+	}
+	return pts
+}
+
+// Circle adds a full circle around (x, y) to the current path as a new subpath.
+func (ctx *Context2D) Circle(x, y, r float64) {
+	ctx.MoveTo(x+r, y)
+	ctx.Arc(x, y, r, 0, 2*math.Pi)
+}
+
+// FillCircle fills a circle of radius r around (x, y) with the current fill style.
+func (ctx *Context2D) FillCircle(x, y, r float64) {
+	ctx.BeginPath()
+	ctx.Arc(x, y, r, 0, 2*math.Pi)
+	ctx.Fill()
+}
+
+// StrokeCircle strokes a circle of radius r around (x, y) with the current stroke style.
+func (ctx *Context2D) StrokeCircle(x, y, r float64) {
+	ctx.BeginPath()
+	ctx.Arc(x, y, r, 0, 2*math.Pi)
+	ctx.Stroke()
+}
+
+// Ellipse adds an ellipse arc centred on (x, y) with radii rx and ry, rotated by
+// rotation, from startAngle to endAngle to the current path. Like Arc it runs
+// clockwise unless counterclockwise is true.
+func (ctx *Context2D) Ellipse(x, y, rx, ry, rotation, startAngle, endAngle float64, counterclockwise ...bool) {
+	ccw := len(counterclockwise) > 0 && counterclockwise[0]
+	ctx.Call("ellipse", x, y, rx, ry, rotation, startAngle, endAngle, ccw)
+}
+
+// FillEllipse fills a full ellipse centred on (x, y) with radii rx and ry, rotated by rotation.
+func (ctx *Context2D) FillEllipse(x, y, rx, ry, rotation float64) {
+	ctx.BeginPath()
+	ctx.Ellipse(x, y, rx, ry, rotation, 0, 2*math.Pi)
+	ctx.Fill()
+}
+
+// StrokeEllipse strokes a full ellipse centred on (x, y) with radii rx and ry, rotated by rotation.
+func (ctx *Context2D) StrokeEllipse(x, y, rx, ry, rotation float64) {
+	ctx.BeginPath()
+	ctx.Ellipse(x, y, rx, ry, rotation, 0, 2*math.Pi)
+	ctx.Stroke()
+}
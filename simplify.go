@@ -0,0 +1,50 @@
+package canvas
+
+import "math"
+
+// SimplifyPolyline reduces pts with the Douglas–Peucker algorithm, dropping every point that lies
+// closer than tolerance to the line through the points kept around it.
+// The first and last points are always kept. The input slice is not modified.
+func SimplifyPolyline(pts []Point, tolerance float64) []Point {
+	if len(pts) < 3 {
+		return append([]Point(nil), pts...)
+	}
+	keep := make([]bool, len(pts))
+	keep[0], keep[len(pts)-1] = true, true
+	stack := [][2]int{{0, len(pts) - 1}}
+	for len(stack) > 0 {
+		span := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		first, last := span[0], span[1]
+		maxDist, index := 0.0, -1
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(pts[i], pts[first], pts[last]); d > maxDist {
+				maxDist, index = d, i
+			}
+		}
+		if index >= 0 && maxDist > tolerance {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+	out := make([]Point, 0, len(pts))
+	for i, k := range keep {
+		if k {
+			out = append(out, pts[i])
+		}
+	}
+	return out
+}
+
+// segmentDistance returns the distance from p to the segment a-b.
+func segmentDistance(p, a, b Point) float64 {
+	d := b.Sub(a)
+	l2 := d.X*d.X + d.Y*d.Y
+	if l2 == 0 {
+		return math.Hypot(p.X-a.X, p.Y-a.Y)
+	}
+	t := ((p.X-a.X)*d.X + (p.Y-a.Y)*d.Y) / l2
+	t = math.Max(0, math.Min(1, t))
+	q := a.Add(d.Mul(t))
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
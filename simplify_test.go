@@ -0,0 +1,46 @@
+package canvas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimplifyPolyline(t *testing.T) {
+	tests := []struct {
+		name      string
+		pts       []Point
+		tolerance float64
+		want      []Point
+	}{
+		{"empty", nil, 1, []Point{}},
+		{"two points", []Point{{0, 0}, {5, 5}}, 1, []Point{{0, 0}, {5, 5}}},
+		{"collinear", []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}, 0.1, []Point{{0, 0}, {3, 0}}},
+		{"small wiggle dropped", []Point{{0, 0}, {5, 0.4}, {10, 0}}, 0.5, []Point{{0, 0}, {10, 0}}},
+		{"corner kept", []Point{{0, 0}, {5, 5}, {10, 0}}, 1, []Point{{0, 0}, {5, 5}, {10, 0}}},
+		{"zero tolerance keeps bends", []Point{{0, 0}, {1, 0}, {2, 1}, {3, 1}}, 0, []Point{{0, 0}, {1, 0}, {2, 1}, {3, 1}}},
+		{
+			"nested spans",
+			[]Point{{0, 0}, {5, 0.2}, {10, 0}, {10, 5.3}, {10, 10}},
+			0.5,
+			[]Point{{0, 0}, {10, 0}, {10, 10}},
+		},
+		{"closed loop", []Point{{0, 0}, {10, 0}, {10, 10}, {0, 0}}, 1, []Point{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+	}
+	for _, tt := range tests {
+		got := SimplifyPolyline(tt.pts, tt.tolerance)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSimplifyPolylineKeepsInput(t *testing.T) {
+	pts := []Point{{0, 0}, {1, 0}, {2, 0}}
+	SimplifyPolyline(pts, 1)
+	if !reflect.DeepEqual(pts, []Point{{0, 0}, {1, 0}, {2, 0}}) {
+		t.Errorf("input modified: %v", pts)
+	}
+}
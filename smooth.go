@@ -0,0 +1,45 @@
+package canvas
+
+// CubicSegment is a cubic Bézier curve from P0 to P3 with control points P1 and P2.
+type CubicSegment struct {
+	P0, P1, P2, P3 Point
+}
+
+// CatmullRom converts a polyline into cubic Bézier segments of a Catmull–Rom spline passing
+// through every point. tension scales the tangents: 1 gives the classic Catmull–Rom curve,
+// 0 straight lines, and larger values rounder curves.
+func CatmullRom(pts []Point, tension float64) []CubicSegment {
+	if len(pts) < 2 {
+		return nil
+	}
+	k := tension / 6
+	segs := make([]CubicSegment, 0, len(pts)-1)
+	for i := 0; i < len(pts)-1; i++ {
+		p0, p1, p2, p3 := pts[i], pts[i], pts[i+1], pts[i+1]
+		if i > 0 {
+			p0 = pts[i-1]
+		}
+		if i+2 < len(pts) {
+			p3 = pts[i+2]
+		}
+		segs = append(segs, CubicSegment{
+			P0: p1,
+			P1: p1.Add(p2.Sub(p0).Mul(k)),
+			P2: p2.Sub(p3.Sub(p1).Mul(k)),
+			P3: p2,
+		})
+	}
+	return segs
+}
+
+// SmoothCurveThrough adds a smooth curve passing through all pts to the current path of ctx,
+// starting with a moveTo to the first point. Fill or stroke the path afterwards.
+func SmoothCurveThrough(ctx *Context2D, pts []Point, tension float64) {
+	if len(pts) == 0 {
+		return
+	}
+	ctx.MoveTo(pts[0].X, pts[0].Y)
+	for _, s := range CatmullRom(pts, tension) {
+		ctx.BezierCurveTo(s.P1.X, s.P1.Y, s.P2.X, s.P2.Y, s.P3.X, s.P3.Y)
+	}
+}
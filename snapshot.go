@@ -0,0 +1,48 @@
+package canvas
+
+// Snapshot holds a copy of the pixels of a canvas in an offscreen canvas, for
+// temporary overlays such as drag previews that are drawn and undone every frame.
+// Restoring is a single drawImage, much cheaper than putImageData.
+type Snapshot struct {
+	c *Canvas
+}
+
+// Snapshot copies the current pixels of the whole canvas.
+func (ctx *Context2D) Snapshot() *Snapshot {
+	return ctx.SnapshotInto(nil)
+}
+
+// SnapshotInto copies the current pixels of the whole canvas into dst and returns it,
+// so a snapshot taken every frame reuses its offscreen canvas. If dst is nil or the
+// canvas size changed a new Snapshot is returned; keep it for the next call.
+func (ctx *Context2D) SnapshotInto(dst *Snapshot) *Snapshot {
+	c := ctx.Canvas()
+	w, h := c.Size()
+	if dst == nil || dst.c == nil {
+		dst = &Snapshot{c: Create(w, h)}
+	} else if sw, sh := dst.c.Size(); sw != w || sh != h {
+		dst.c.Set("width", w)
+		dst.c.Set("height", h)
+	}
+	sctx := dst.c.GetContext2D()
+	sctx.SetCompositeOp(CompositeCopy)
+	sctx.DrawImage(c, 0, 0, float64(w), float64(h))
+	return dst
+}
+
+// RestoreSnapshot replaces every pixel of the canvas with the pixels of s, ignoring
+// the current transform, alpha and composite operation. The clip region still applies.
+func (ctx *Context2D) RestoreSnapshot(s *Snapshot) {
+	w, h := s.c.Size()
+	ctx.Save()
+	ctx.SetTransform(1, 0, 0, 1, 0, 0)
+	ctx.GlobalAlpha = 1
+	ctx.SetCompositeOp(CompositeCopy)
+	ctx.DrawImage(s.c, 0, 0, float64(w), float64(h))
+	ctx.Restore()
+}
+
+// Canvas returns the offscreen canvas holding the pixels, e.g. to draw a thumbnail of it.
+func (s *Snapshot) Canvas() *Canvas {
+	return s.c
+}
@@ -0,0 +1,176 @@
+package canvas
+
+import "math"
+
+// SpriteSheet describes an image made of equally sized frames laid out in rows.
+type SpriteSheet struct {
+	Image CanvasImageSource
+	// FrameWidth and FrameHeight are the size of a single frame in image pixels.
+	FrameWidth, FrameHeight float64
+	// Columns is the number of frames per row. If zero it is derived from the
+	// width of the image once it has loaded.
+	Columns int
+}
+
+// NewSpriteSheet creates a SpriteSheet cutting img into frames of the given size.
+func NewSpriteSheet(img CanvasImageSource, frameWidth, frameHeight float64) *SpriteSheet {
+	return &SpriteSheet{Image: img, FrameWidth: frameWidth, FrameHeight: frameHeight}
+}
+
+// Frame returns the source rectangle of frame i, counting left to right, top to bottom.
+func (s *SpriteSheet) Frame(i int) Rect {
+	cols := s.Columns
+	if cols <= 0 {
+		w, _ := SourceSize(s.Image)
+		cols = int(w / s.FrameWidth)
+		if cols <= 0 {
+			cols = 1
+		}
+	}
+	return Rect{
+		X: float64(i%cols) * s.FrameWidth,
+		Y: float64(i/cols) * s.FrameHeight,
+		W: s.FrameWidth,
+		H: s.FrameHeight,
+	}
+}
+
+// DrawFrame draws frame i with its top-left corner at (x, y) at its natural size.
+func (s *SpriteSheet) DrawFrame(ctx *Context2D, i int, x, y float64) {
+	f := s.Frame(i)
+	ctx.DrawImageRegion(s.Image, f.X, f.Y, f.W, f.H, x, y, f.W, f.H)
+}
+
+// LoopMode selects what an animation sequence does after its last frame.
+type LoopMode int
+
+const (
+	// LoopForever restarts the sequence from the first frame.
+	LoopForever LoopMode = iota
+	// LoopOnce stops on the last frame and reports completion.
+	LoopOnce
+	// LoopPingPong plays the sequence backwards and forwards forever.
+	LoopPingPong
+)
+
+// Sequence is a named run of sprite sheet frames.
+type Sequence struct {
+	// Frames are indices into the sprite sheet.
+	Frames []int
+	// Durations of each frame in milliseconds. A single value applies to all frames.
+	Durations []float64
+	Loop      LoopMode
+}
+
+func (s *Sequence) duration(i int) float64 {
+	if len(s.Durations) == 0 {
+		return 100
+	}
+	if i < len(s.Durations) {
+		return s.Durations[i]
+	}
+	return s.Durations[len(s.Durations)-1]
+}
+
+// order returns the frame positions of one cycle of the sequence.
+func (s *Sequence) order() []int {
+	n := len(s.Frames)
+	order := make([]int, 0, 2*n)
+	for i := 0; i < n; i++ {
+		order = append(order, i)
+	}
+	if s.Loop == LoopPingPong {
+		for i := n - 2; i > 0; i-- {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// Animation plays named frame sequences from a SpriteSheet.
+// Time is passed explicitly in milliseconds, e.g. FrameInfo.Time from a Ticker.
+type Animation struct {
+	Sheet *SpriteSheet
+	// OnComplete is called once when a LoopOnce sequence reaches its last frame.
+	OnComplete func(name string)
+
+	seqs     map[string]*Sequence
+	current  string
+	start    float64
+	started  bool
+	finished bool
+}
+
+// NewAnimation creates an Animation without sequences.
+func NewAnimation(sheet *SpriteSheet) *Animation {
+	return &Animation{Sheet: sheet, seqs: map[string]*Sequence{}}
+}
+
+// Add registers a sequence under name, replacing any previous one.
+func (a *Animation) Add(name string, seq Sequence) {
+	a.seqs[name] = &seq
+}
+
+// Play switches to the sequence name, starting it at time t.
+// Playing the current sequence again restarts it.
+func (a *Animation) Play(name string, t float64) {
+	a.current = name
+	a.start = t
+	a.started = true
+	a.finished = false
+}
+
+// Current returns the name of the playing sequence.
+func (a *Animation) Current() string {
+	return a.current
+}
+
+// Finished reports whether a LoopOnce sequence has reached its end.
+func (a *Animation) Finished() bool {
+	return a.finished
+}
+
+// FrameAt returns the sprite sheet frame index shown at time t, or -1 if nothing is playing.
+func (a *Animation) FrameAt(t float64) int {
+	seq := a.seqs[a.current]
+	if !a.started || seq == nil || len(seq.Frames) == 0 {
+		return -1
+	}
+	order := seq.order()
+	var total float64
+	for _, i := range order {
+		total += seq.duration(i)
+	}
+	elapsed := t - a.start
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if seq.Loop == LoopOnce {
+		if elapsed >= total {
+			if !a.finished {
+				a.finished = true
+				if a.OnComplete != nil {
+					a.OnComplete(a.current)
+				}
+			}
+			return seq.Frames[len(seq.Frames)-1]
+		}
+	} else if total > 0 {
+		elapsed = math.Mod(elapsed, total)
+	}
+	for _, i := range order {
+		d := seq.duration(i)
+		if elapsed < d {
+			return seq.Frames[i]
+		}
+		elapsed -= d
+	}
+	return seq.Frames[order[len(order)-1]]
+}
+
+// Draw draws the frame shown at time t with its top-left corner at (x, y).
+func (a *Animation) Draw(ctx *Context2D, x, y, t float64) {
+	if f := a.FrameAt(t); f >= 0 {
+		a.Sheet.DrawFrame(ctx, f, x, y)
+	}
+}
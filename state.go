@@ -0,0 +1,93 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// State is a snapshot of the drawing state of a Context2D held in plain Go values.
+// Unlike Save/Restore it can be inspected, compared, stored and applied to another context.
+// The clipping region is not part of it since the Canvas API offers no way to read it.
+type State struct {
+	// StrokeStyle and FillStyle are either a CSS color string or the *js.Object of a
+	// gradient or pattern.
+	StrokeStyle, FillStyle interface{}
+
+	ShadowColor   string
+	ShadowBlur    float64
+	ShadowOffsetX float64
+	ShadowOffsetY float64
+
+	LineCap        LineCap
+	LineJoin       LineJoin
+	LineWidth      float64
+	MiterLimit     float64
+	LineDash       []float64
+	LineDashOffset float64
+
+	Font         string
+	TextAlign    TextAlign
+	TextBaseline TextBaseline
+
+	GlobalAlpha              float64
+	GlobalCompositeOperation CompositeOp
+	ImageSmoothingEnabled    bool
+
+	// Transform is the current transformation matrix as (a, b, c, d, e, f).
+	Transform [6]float64
+}
+
+// GetState captures the current drawing state of ctx.
+func (ctx *Context2D) GetState() *State {
+	s := &State{
+		StrokeStyle:              styleValue(ctx.Get("strokeStyle")),
+		FillStyle:                styleValue(ctx.Get("fillStyle")),
+		ShadowColor:              ctx.ShadowColor,
+		ShadowBlur:               ctx.ShadowBlur,
+		ShadowOffsetX:            ctx.ShadowOffsetX,
+		ShadowOffsetY:            ctx.ShadowOffsetY,
+		LineCap:                  ctx.LineCap,
+		LineJoin:                 ctx.LineJoin,
+		LineWidth:                ctx.LineWidth,
+		MiterLimit:               ctx.MiterLimit,
+		LineDashOffset:           ctx.LineDashOffset,
+		Font:                     ctx.Font,
+		TextAlign:                ctx.TextAlign,
+		TextBaseline:             ctx.TextBaseline,
+		GlobalAlpha:              ctx.GlobalAlpha,
+		GlobalCompositeOperation: ctx.GlobalCompositeOperation,
+		ImageSmoothingEnabled:    ctx.Get("imageSmoothingEnabled").Bool(),
+		LineDash:                 ctx.GetLineDash(),
+	}
+	s.Transform = ctx.GetMatrix().Array()
+	return s
+}
+
+// SetState applies a previously captured state to ctx, replacing its current transform.
+func (ctx *Context2D) SetState(s *State) {
+	ctx.Set("strokeStyle", s.StrokeStyle)
+	ctx.Set("fillStyle", s.FillStyle)
+	ctx.ShadowColor = s.ShadowColor
+	ctx.ShadowBlur = s.ShadowBlur
+	ctx.ShadowOffsetX = s.ShadowOffsetX
+	ctx.ShadowOffsetY = s.ShadowOffsetY
+	ctx.LineCap = s.LineCap
+	ctx.LineJoin = s.LineJoin
+	ctx.LineWidth = s.LineWidth
+	ctx.MiterLimit = s.MiterLimit
+	ctx.SetLineDash(s.LineDash...)
+	ctx.LineDashOffset = s.LineDashOffset
+	ctx.Font = s.Font
+	ctx.TextAlign = s.TextAlign
+	ctx.TextBaseline = s.TextBaseline
+	ctx.GlobalAlpha = s.GlobalAlpha
+	ctx.GlobalCompositeOperation = s.GlobalCompositeOperation
+	ctx.Set("imageSmoothingEnabled", s.ImageSmoothingEnabled)
+	t := s.Transform
+	ctx.SetTransform(t[0], t[1], t[2], t[3], t[4], t[5])
+}
+
+// styleValue keeps gradients and patterns as JS objects instead of converting them to Go maps.
+func styleValue(o *js.Object) interface{} {
+	if o.Get("constructor") == js.Global.Get("String") {
+		return o.String()
+	}
+	return o
+}
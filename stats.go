@@ -0,0 +1,132 @@
+package canvas
+
+import (
+	"fmt"
+	"math"
+)
+
+// StatsOverlay keeps track of frame times and draws a small panel with the frame rate,
+// a graph of recent frame times and, if Metrics is set, the draw-call count of the
+// last frame. Call Update once per frame and Draw at the end of rendering.
+type StatsOverlay struct {
+	// X and Y position the panel in the current coordinate space.
+	X, Y float64
+	// Width and Height of the panel, 100 x 48 by default.
+	Width, Height float64
+	// Metrics, if set, supplies the draw-call count shown in the panel.
+	// Its EndFrame is called by Update.
+	Metrics *Metrics
+	// Budget is the frame time in milliseconds drawn as a reference line, 1000/60 by default.
+	Budget float64
+
+	times []float64
+	next  int
+	fps   float64
+	calls int
+}
+
+// NewStatsOverlay creates a panel at x, y keeping the frame times of the last 100 frames.
+func NewStatsOverlay(x, y float64) *StatsOverlay {
+	return &StatsOverlay{
+		X:      x,
+		Y:      y,
+		Width:  100,
+		Height: 48,
+		Budget: 1000.0 / 60,
+		times:  make([]float64, 0, 100),
+	}
+}
+
+// Update records the frame described by f.
+func (s *StatsOverlay) Update(f FrameInfo) {
+	if f.Delta > 0 {
+		if len(s.times) < cap(s.times) {
+			s.times = append(s.times, f.Delta)
+		} else {
+			s.times[s.next] = f.Delta
+			s.next = (s.next + 1) % len(s.times)
+		}
+		// smooth the rate so the number stays readable
+		fps := 1000 / f.Delta
+		if s.fps == 0 {
+			s.fps = fps
+		} else {
+			s.fps += (fps - s.fps) * 0.05
+		}
+	}
+	if s.Metrics != nil {
+		s.calls = s.Metrics.EndFrame().DrawCalls
+	}
+}
+
+// FPS returns the smoothed frame rate.
+func (s *StatsOverlay) FPS() float64 {
+	return s.fps
+}
+
+// FrameTime returns the mean and the worst frame time in milliseconds over the recorded frames.
+func (s *StatsOverlay) FrameTime() (mean, worst float64) {
+	if len(s.times) == 0 {
+		return 0, 0
+	}
+	for _, t := range s.times {
+		mean += t
+		worst = math.Max(worst, t)
+	}
+	return mean / float64(len(s.times)), worst
+}
+
+// Draw paints the panel on ctx.
+func (s *StatsOverlay) Draw(ctx *Context2D) {
+	// the panel itself should not show up in the counters, its save and restore included
+	if s.Metrics != nil {
+		s.Metrics.paused = true
+	}
+	ctx.Save()
+	defer func() {
+		ctx.Restore()
+		if s.Metrics != nil {
+			s.Metrics.paused = false
+		}
+	}()
+	x, y, w, h := s.X, s.Y, s.Width, s.Height
+	ctx.GlobalAlpha = 1
+	ctx.SetCompositeOp(CompositeSourceOver)
+	ctx.FillStyle = "rgba(0,0,0,0.75)"
+	ctx.FillRect(x, y, w, h)
+
+	mean, worst := s.FrameTime()
+	label := fmt.Sprintf("%.0f FPS %.1fms", s.fps, mean)
+	if s.Metrics != nil {
+		label += fmt.Sprintf(" %dc", s.calls)
+	}
+	ctx.Font = "10px monospace"
+	ctx.SetTextBaseline(TextBaselineTop)
+	ctx.SetTextAlign(TextAlignLeft)
+	ctx.FillStyle = "#0f0"
+	ctx.FillText(label, x+3, y+2, w-6)
+
+	gx, gy, gw, gh := x+3, y+15, w-6, h-18
+	scale := math.Max(worst, 2*s.Budget)
+	n := len(s.times)
+	if n > 0 {
+		bar := gw / float64(cap(s.times))
+		for i := 0; i < n; i++ {
+			t := s.times[(s.next+i)%n]
+			bh := math.Min(t/scale, 1) * gh
+			if t > s.Budget*1.5 {
+				ctx.FillStyle = "#f44"
+			} else {
+				ctx.FillStyle = "#0f0"
+			}
+			ctx.FillRect(gx+float64(i)*bar, gy+gh-bh, math.Max(bar-0.5, 0.5), bh)
+		}
+	}
+	by := math.Floor(gy+gh-s.Budget/scale*gh) + 0.5
+	ctx.StrokeStyle = "rgba(255,255,255,0.5)"
+	ctx.LineWidth = 1
+	ctx.BeginPath()
+	ctx.MoveTo(gx, by)
+	ctx.LineTo(gx+gw, by)
+	ctx.Stroke()
+}
@@ -0,0 +1,165 @@
+package canvas
+
+import "math"
+
+type tileKey struct {
+	X, Y int
+}
+
+// InfiniteSurface is an unbounded drawing surface for whiteboard-like apps. Content
+// is stored in a sparse grid of offscreen tile canvases that are created on demand
+// wherever something is drawn, and the visible part is rendered through a Camera.
+type InfiniteSurface struct {
+	// TileSize is the side of a tile in world units. Values that are not positive
+	// and finite are treated as 256.
+	TileSize float64
+	// Resolution is the number of tile pixels per world unit. Values above 1 keep
+	// content sharp when zooming in at the cost of memory.
+	Resolution float64
+
+	tiles map[tileKey]*Canvas
+}
+
+// NewInfiniteSurface creates an empty surface with tiles of tileSize world units
+// stored at one pixel per world unit. A tileSize that is not positive and finite
+// is replaced by 256.
+func NewInfiniteSurface(tileSize float64) *InfiniteSurface {
+	if !validTileSize(tileSize) {
+		tileSize = defaultTileSize
+	}
+	return &InfiniteSurface{TileSize: tileSize, Resolution: 1, tiles: map[tileKey]*Canvas{}}
+}
+
+const defaultTileSize = 256
+
+func validTileSize(v float64) bool {
+	return v > 0 && !math.IsInf(v, 1)
+}
+
+// tileSize returns TileSize, or the default if it was set to an unusable value.
+func (s *InfiniteSurface) tileSize() float64 {
+	if !validTileSize(s.TileSize) {
+		return defaultTileSize
+	}
+	return s.TileSize
+}
+
+// Draw runs fn once for every tile touched by bounds, creating missing tiles, with
+// the context transformed so that fn draws in world coordinates and clipped to the
+// tile. bounds must cover everything fn draws, line widths included; content outside
+// it may be cut off at tile edges.
+func (s *InfiniteSurface) Draw(bounds Rect, fn func(ctx *Context2D)) {
+	x0, y0, x1, y1 := s.tileRange(bounds)
+	for ty := y0; ty <= y1; ty++ {
+		for tx := x0; tx <= x1; tx++ {
+			ctx := s.tile(tileKey{tx, ty}, true).GetContext2D()
+			ctx.Save()
+			ctx.Scale(s.Resolution, s.Resolution)
+			ctx.Translate(-float64(tx)*s.tileSize(), -float64(ty)*s.tileSize())
+			fn(ctx)
+			ctx.Restore()
+		}
+	}
+}
+
+// Render draws the tiles visible through cam onto ctx, which should be set up to
+// draw in screen units, e.g. the HiDPI context of the viewport canvas.
+func (s *InfiniteSurface) Render(ctx *Context2D, cam *Camera) {
+	x0, y0, x1, y1 := s.tileRange(cam.VisibleBounds())
+	ctx.Save()
+	cam.Apply(ctx)
+	for ty := y0; ty <= y1; ty++ {
+		for tx := x0; tx <= x1; tx++ {
+			t := s.tile(tileKey{tx, ty}, false)
+			if t == nil {
+				continue
+			}
+			ctx.DrawImage(t, float64(tx)*s.tileSize(), float64(ty)*s.tileSize(), s.tileSize(), s.tileSize())
+		}
+	}
+	ctx.Restore()
+}
+
+// Bounds returns the world rectangle covered by the existing tiles.
+func (s *InfiniteSurface) Bounds() Rect {
+	if len(s.tiles) == 0 {
+		return Rect{}
+	}
+	first := true
+	var x0, y0, x1, y1 int
+	for k := range s.tiles {
+		if first || k.X < x0 {
+			x0 = k.X
+		}
+		if first || k.Y < y0 {
+			y0 = k.Y
+		}
+		if first || k.X > x1 {
+			x1 = k.X
+		}
+		if first || k.Y > y1 {
+			y1 = k.Y
+		}
+		first = false
+	}
+	return Rect{
+		X: float64(x0) * s.tileSize(),
+		Y: float64(y0) * s.tileSize(),
+		W: float64(x1-x0+1) * s.tileSize(),
+		H: float64(y1-y0+1) * s.tileSize(),
+	}
+}
+
+// Tiles returns the number of allocated tiles.
+func (s *InfiniteSurface) Tiles() int {
+	return len(s.tiles)
+}
+
+// Clear removes all tiles.
+func (s *InfiniteSurface) Clear() {
+	s.tiles = map[tileKey]*Canvas{}
+}
+
+// ClearRect removes the tiles lying entirely inside r and clears the covered part
+// of the others.
+func (s *InfiniteSurface) ClearRect(r Rect) {
+	x0, y0, x1, y1 := s.tileRange(r)
+	for ty := y0; ty <= y1; ty++ {
+		for tx := x0; tx <= x1; tx++ {
+			k := tileKey{tx, ty}
+			if s.tiles[k] == nil {
+				continue
+			}
+			tr := Rect{X: float64(tx) * s.tileSize(), Y: float64(ty) * s.tileSize(), W: s.tileSize(), H: s.tileSize()}
+			if r.X <= tr.X && r.Y <= tr.Y && r.X+r.W >= tr.X+tr.W && r.Y+r.H >= tr.Y+tr.H {
+				delete(s.tiles, k)
+				continue
+			}
+			ctx := s.tiles[k].GetContext2D()
+			ctx.Save()
+			ctx.Scale(s.Resolution, s.Resolution)
+			ctx.Translate(-tr.X, -tr.Y)
+			ctx.ClearRect(r.X, r.Y, r.W, r.H)
+			ctx.Restore()
+		}
+	}
+}
+
+func (s *InfiniteSurface) tileRange(r Rect) (x0, y0, x1, y1 int) {
+	size := s.tileSize()
+	x0 = int(math.Floor(r.X / size))
+	y0 = int(math.Floor(r.Y / size))
+	x1 = int(math.Floor((r.X + r.W) / size))
+	y1 = int(math.Floor((r.Y + r.H) / size))
+	return
+}
+
+func (s *InfiniteSurface) tile(k tileKey, create bool) *Canvas {
+	t := s.tiles[k]
+	if t == nil && create {
+		n := int(math.Ceil(s.tileSize() * s.Resolution))
+		t = Create(n, n)
+		s.tiles[k] = t
+	}
+	return t
+}
@@ -0,0 +1,61 @@
+package canvas
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ErrSVGLoad is passed to the DrawSVG callback when the browser fails to load the markup as an image.
+var ErrSVGLoad = errors.New("canvas: failed to load SVG image")
+
+// DrawSVG rasterizes the SVG document svgMarkup into the rectangle x, y, w, h of ctx.
+// The markup is loaded through an <img> from a blob URL, so drawing happens
+// asynchronously; onDone, if not nil, is called afterwards with nil or ErrSVGLoad.
+// The current state of ctx at that time, transform included, applies.
+//
+// An xmlns attribute is added to the root element if it is missing, since browsers
+// refuse to load SVG images without it. SVG images cannot load external resources,
+// and in some browsers an SVG containing <foreignObject> taints the canvas, making
+// GetImageData and ToDataURL fail afterwards.
+func DrawSVG(ctx *Context2D, svgMarkup string, x, y, w, h float64, onDone func(error)) {
+	if !strings.Contains(svgMarkup, "xmlns=") {
+		if i := strings.Index(svgMarkup, "<svg"); i >= 0 {
+			i += len("<svg")
+			svgMarkup = svgMarkup[:i] + ` xmlns="http://www.w3.org/2000/svg"` + svgMarkup[i:]
+		}
+	}
+	blob := js.Global.Get("Blob").New([]interface{}{svgMarkup}, js.M{"type": "image/svg+xml;charset=utf-8"})
+	loadBlobImage(blob, func(img *js.Object, ok bool) {
+		var err error
+		if ok {
+			ctx.DrawImage(JSImage{img}, x, y, w, h)
+		} else {
+			err = ErrSVGLoad
+		}
+		if onDone != nil {
+			onDone(err)
+		}
+	})
+}
+
+// loadBlobImage loads blob into a new <img> through an object URL, which is revoked
+// again before done is called.
+func loadBlobImage(blob *js.Object, done func(img *js.Object, ok bool)) {
+	url := js.Global.Get("URL").Call("createObjectURL", blob)
+	img := js.Global.Get("Image").New()
+	finish := func(ok bool) {
+		img.Set("onload", nil)
+		img.Set("onerror", nil)
+		js.Global.Get("URL").Call("revokeObjectURL", url)
+		done(img, ok)
+	}
+	img.Set("onload", func() {
+		finish(true)
+	})
+	img.Set("onerror", func() {
+		finish(false)
+	})
+	img.Set("src", url)
+}
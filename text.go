@@ -0,0 +1,116 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// DrawTextOutlined draws text at x, y with an outline: it strokes the glyphs with
+// stroke and lineWidth first and fills them with fill on top, so the inner half of
+// the stroke is covered and the outline never eats into the letters. Round joins
+// keep sharp glyph corners from producing miter spikes. The state of ctx is left unchanged.
+func (ctx *Context2D) DrawTextOutlined(text string, x, y float64, fill, stroke Style, lineWidth float64) {
+	ctx.Save()
+	defer ctx.Restore()
+	ctx.SetLineJoin(LineJoinRound)
+	ctx.MiterLimit = 2
+	ctx.LineWidth = lineWidth
+	ctx.StrokeStyle = jsStyle(stroke)
+	ctx.StrokeText(text, x, y)
+	ctx.FillStyle = jsStyle(fill)
+	ctx.FillText(text, x, y)
+}
+
+// TextMetrics is the result of Context2D.MeasureText. Bounding box distances are
+// measured from the text position given to FillText, following the current
+// TextAlign and TextBaseline; positive Left extends to the left and positive Ascent upwards.
+type TextMetrics struct {
+	*js.Object
+	// Width is the advance width of the text.
+	Width float64 `js:"width"`
+	// ActualBoundingBoxLeft, Right, Ascent and Descent bound the inked glyphs.
+	ActualBoundingBoxLeft    float64 `js:"actualBoundingBoxLeft"`
+	ActualBoundingBoxRight   float64 `js:"actualBoundingBoxRight"`
+	ActualBoundingBoxAscent  float64 `js:"actualBoundingBoxAscent"`
+	ActualBoundingBoxDescent float64 `js:"actualBoundingBoxDescent"`
+	// FontBoundingBoxAscent and Descent bound all glyphs of the font.
+	FontBoundingBoxAscent  float64 `js:"fontBoundingBoxAscent"`
+	FontBoundingBoxDescent float64 `js:"fontBoundingBoxDescent"`
+}
+
+// MeasureText measures text with the current font and alignment.
+func (ctx *Context2D) MeasureText(text string) *TextMetrics {
+	return &TextMetrics{Object: ctx.Call("measureText", text)}
+}
+
+// TextBounds returns the box covered by the glyphs of text drawn at x, y with the
+// current font and alignment. Engines without the actualBoundingBox metrics get an
+// estimate from the advance width and the font or em size instead.
+func (ctx *Context2D) TextBounds(text string, x, y float64) Rect {
+	m := ctx.MeasureText(text)
+	left, right := 0.0, m.Width
+	if m.defined("actualBoundingBoxLeft") && (m.ActualBoundingBoxLeft != 0 || m.ActualBoundingBoxRight != 0) {
+		left, right = m.ActualBoundingBoxLeft, m.ActualBoundingBoxRight
+	}
+	var ascent, descent float64
+	switch {
+	case m.defined("actualBoundingBoxAscent"):
+		ascent, descent = m.ActualBoundingBoxAscent, m.ActualBoundingBoxDescent
+	case m.defined("fontBoundingBoxAscent"):
+		ascent, descent = m.FontBoundingBoxAscent, m.FontBoundingBoxDescent
+	default:
+		// assume an alphabetic baseline and a capital M about as wide as the em
+		em := ctx.MeasureText("M").Width
+		ascent, descent = em, em/4
+	}
+	return Rect{
+		X: x - left,
+		Y: y - ascent,
+		W: left + right,
+		H: ascent + descent,
+	}
+}
+
+// defined reports whether the engine provides the metric field, which older ones lack.
+func (m *TextMetrics) defined(field string) bool {
+	v := m.Get(field)
+	return v != js.Undefined && !math.IsNaN(v.Float())
+}
+
+// FillTextGradient fills text with a linear gradient spanning the glyph bounds
+// in the direction of angle, in radians clockwise from the positive x axis, so
+// the stops cover the string rather than the whole canvas.
+func (ctx *Context2D) FillTextGradient(text string, x, y, angle float64, stops []Stop) {
+	b := ctx.TextBounds(text, x, y)
+	sin, cos := math.Sincos(angle)
+	length := math.Abs(b.W*cos) + math.Abs(b.H*sin)
+	ctx.Save()
+	ctx.FillStyle = NewLinearGradientAngle(ctx, b.X+b.W/2, b.Y+b.H/2, length, angle, stops).Value()
+	ctx.FillText(text, x, y)
+	ctx.Restore()
+}
+
+// FillTextPattern fills text with a pattern of img whose origin is the top left
+// corner of the glyph bounds. If stretch is set the image is scaled to cover the
+// bounds exactly instead of being tiled at its natural size.
+func (ctx *Context2D) FillTextPattern(text string, x, y float64, img CanvasImageSource, stretch bool) {
+	b := ctx.TextBounds(text, x, y)
+	rep := PatternRepeat
+	if stretch {
+		rep = PatternNoRepeat
+	}
+	p := ctx.CreatePattern(img, rep)
+	if p.Value() == nil {
+		return
+	}
+	sx, sy := 1.0, 1.0
+	if iw, ih := SourceSize(img); stretch && iw > 0 && ih > 0 {
+		sx, sy = b.W/iw, b.H/ih
+	}
+	p.SetTransform(sx, 0, 0, sy, b.X, b.Y)
+	ctx.Save()
+	ctx.FillStyle = p.Value()
+	ctx.FillText(text, x, y)
+	ctx.Restore()
+}
@@ -0,0 +1,78 @@
+package canvas
+
+import "math"
+
+// VerticalMode selects how FillTextVertical lays out its text.
+type VerticalMode int
+
+const (
+	// VerticalRotatedCCW rotates the whole string 90° counterclockwise so it reads
+	// bottom to top, the usual orientation of a chart's y axis label.
+	VerticalRotatedCCW VerticalMode = iota
+	// VerticalRotatedCW rotates the whole string 90° clockwise so it reads top to bottom.
+	VerticalRotatedCW
+	// VerticalStacked keeps every character upright and stacks them top to bottom,
+	// as in vertical CJK writing.
+	VerticalStacked
+)
+
+// VerticalTextOptions configures FillTextVertical.
+type VerticalTextOptions struct {
+	Mode VerticalMode
+	// Anchor places the text on the y coordinate: 0 puts the top of the text
+	// there, 0.5 its middle and 1 its bottom.
+	Anchor float64
+	// LineHeight is the advance per character in VerticalStacked mode. If 0 the
+	// font's ascent plus descent is used.
+	LineHeight float64
+}
+
+// FillTextVertical draws text in a vertical column centered horizontally on x,
+// using the current font and fill style. The text alignment and baseline of ctx
+// are left unchanged.
+func (ctx *Context2D) FillTextVertical(text string, x, y float64, opts VerticalTextOptions) {
+	length := ctx.MeasureTextVertical(text, opts)
+	top := y - opts.Anchor*length
+	ctx.Save()
+	defer ctx.Restore()
+	ctx.SetTextBaseline(TextBaselineMiddle)
+	switch opts.Mode {
+	case VerticalRotatedCCW:
+		ctx.SetTextAlign(TextAlignLeft)
+		ctx.Translate(x, top+length)
+		ctx.Rotate(-math.Pi / 2)
+		ctx.FillText(text, 0, 0)
+	case VerticalRotatedCW:
+		ctx.SetTextAlign(TextAlignLeft)
+		ctx.Translate(x, top)
+		ctx.Rotate(math.Pi / 2)
+		ctx.FillText(text, 0, 0)
+	default:
+		ctx.SetTextAlign(TextAlignCenter)
+		adv := ctx.verticalAdvance(opts)
+		for i, g := range Graphemes(text) {
+			ctx.FillText(g, x, top+(float64(i)+0.5)*adv)
+		}
+	}
+}
+
+// MeasureTextVertical returns the height of the column FillTextVertical draws
+// for text with the current font.
+func (ctx *Context2D) MeasureTextVertical(text string, opts VerticalTextOptions) float64 {
+	if opts.Mode != VerticalStacked {
+		return ctx.MeasureText(text).Width
+	}
+	return float64(len(Graphemes(text))) * ctx.verticalAdvance(opts)
+}
+
+func (ctx *Context2D) verticalAdvance(opts VerticalTextOptions) float64 {
+	if opts.LineHeight > 0 {
+		return opts.LineHeight
+	}
+	m := ctx.MeasureText("M")
+	if h := m.FontBoundingBoxAscent + m.FontBoundingBoxDescent; h > 0 {
+		return h
+	}
+	// older engines lack font metrics; the em width is close to the font size
+	return m.Width * 1.2
+}
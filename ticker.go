@@ -0,0 +1,154 @@
+package canvas
+
+import "github.com/gopherjs/gopherjs/js"
+
+// FrameInfo describes a single animation frame.
+type FrameInfo struct {
+	// Time is the DOMHighResTimeStamp passed to the requestAnimationFrame callback, in milliseconds.
+	Time float64
+	// Delta is the time elapsed since the previous frame in milliseconds, 0 for the first frame.
+	Delta float64
+	// Frame counts the frames delivered since the ticker was started, starting at 0.
+	Frame int
+}
+
+// Ticker calls a function once per animation frame using window.requestAnimationFrame.
+type Ticker struct {
+	// PauseWhenHidden suspends the ticker while document.visibilityState is "hidden",
+	// so background tabs don't burn CPU. After resuming, the first frame has a Delta of 0
+	// instead of the time spent hidden. It must be set before Start.
+	PauseWhenHidden bool
+	// OnPause and OnResume are called when the ticker is suspended or resumed
+	// because of a visibility change.
+	OnPause, OnResume func()
+
+	fn         func(FrameInfo)
+	cb         *js.Object
+	handle     *js.Object
+	running    bool
+	paused     bool
+	visibility *js.Object
+	last       float64
+	frame      int
+}
+
+// NewTicker creates a stopped Ticker calling fn on every animation frame.
+// fn runs inside the requestAnimationFrame callback and must not block.
+func NewTicker(fn func(FrameInfo)) *Ticker {
+	t := &Ticker{fn: fn}
+	t.cb = js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		t.tick(args[0].Float())
+		return nil
+	})
+	return t
+}
+
+// Start begins delivering frames. Starting a running ticker does nothing.
+func (t *Ticker) Start() {
+	if t.running {
+		return
+	}
+	t.running = true
+	t.last = -1
+	t.frame = 0
+	if t.PauseWhenHidden {
+		t.watchVisibility()
+		if js.Global.Get("document").Get("visibilityState").String() == "hidden" {
+			t.paused = true
+			return
+		}
+	}
+	t.request()
+}
+
+// Stop cancels any pending frame. The ticker can be started again later.
+func (t *Ticker) Stop() {
+	if !t.running {
+		return
+	}
+	t.running = false
+	t.paused = false
+	if t.visibility != nil {
+		js.Global.Get("document").Call("removeEventListener", "visibilitychange", t.visibility)
+		t.visibility = nil
+	}
+	js.Global.Call("cancelAnimationFrame", t.handle)
+}
+
+// Paused reports whether a running ticker is suspended because the page is hidden.
+func (t *Ticker) Paused() bool {
+	return t.paused
+}
+
+func (t *Ticker) watchVisibility() {
+	doc := js.Global.Get("document")
+	t.visibility = js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		hidden := doc.Get("visibilityState").String() == "hidden"
+		switch {
+		case hidden && !t.paused:
+			t.paused = true
+			js.Global.Call("cancelAnimationFrame", t.handle)
+			if t.OnPause != nil {
+				t.OnPause()
+			}
+		case !hidden && t.paused:
+			t.paused = false
+			t.last = -1
+			t.request()
+			if t.OnResume != nil {
+				t.OnResume()
+			}
+		}
+		return nil
+	})
+	doc.Call("addEventListener", "visibilitychange", t.visibility)
+}
+
+// Running reports whether the ticker is started.
+func (t *Ticker) Running() bool {
+	return t.running
+}
+
+func (t *Ticker) request() {
+	t.handle = js.Global.Call("requestAnimationFrame", t.cb)
+}
+
+func (t *Ticker) tick(now float64) {
+	if !t.running || t.paused {
+		return
+	}
+	info := FrameInfo{Time: now, Frame: t.frame}
+	if t.last >= 0 {
+		info.Delta = now - t.last
+	}
+	t.last = now
+	t.frame++
+	t.request()
+	t.fn(info)
+}
+
+// NewFrameChan creates a Ticker delivering every animation frame on the returned channel,
+// for use in select based render loops. The channel is buffered by one frame;
+// frames arriving while the receiver is still busy are dropped, so Delta of the next
+// delivered frame may span several display refreshes.
+// The ticker is returned stopped, so PauseWhenHidden, OnPause and OnResume can be set
+// before calling Start; Stop halts it and the channel is not closed.
+func NewFrameChan() (frames <-chan FrameInfo, t *Ticker) {
+	ch := make(chan FrameInfo, 1)
+	var last float64 = -1
+	t = NewTicker(func(f FrameInfo) {
+		if f.Delta == 0 {
+			// first frame after Start or after resuming from a hidden page
+			last = -1
+		}
+		if last >= 0 {
+			f.Delta = f.Time - last
+		}
+		select {
+		case ch <- f:
+			last = f.Time
+		default:
+		}
+	})
+	return ch, t
+}
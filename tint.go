@@ -0,0 +1,48 @@
+package canvas
+
+import (
+	"image/color"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// tintCache maps image source objects to a Map from CSS color to tinted canvas. A
+// WeakMap lets the variants be collected together with their source image.
+var tintCache *js.Object
+
+// TintImage returns a copy of img recolored with c: the opaque parts of the image
+// are painted over with c using source-atop compositing, so the alpha of c sets the
+// strength of the tint and transparent areas stay transparent. Results are cached
+// per image and color, so calling it every frame, e.g. for a damage flash, is cheap.
+// Call ForgetTints after the content of a canvas used as img changes.
+func TintImage(img CanvasImageSource, c color.Color) *Canvas {
+	src := img.ImageSource()
+	css := cssColor(c)
+	if tintCache == nil {
+		tintCache = js.Global.Get("WeakMap").New()
+	}
+	variants := tintCache.Call("get", src)
+	if variants == js.Undefined {
+		variants = js.Global.Get("Map").New()
+		tintCache.Call("set", src, variants)
+	}
+	if v := variants.Call("get", css); v != js.Undefined {
+		return WrapCanvas(v)
+	}
+	w, h := SourceSize(img)
+	out := Create(int(w), int(h))
+	ctx := out.GetContext2D()
+	ctx.DrawImage(img, 0, 0, w, h)
+	ctx.SetCompositeOp(CompositeSourceAtop)
+	ctx.FillStyle = css
+	ctx.FillRect(0, 0, w, h)
+	variants.Call("set", css, out.Object)
+	return out
+}
+
+// ForgetTints drops the cached tinted variants of img.
+func ForgetTints(img CanvasImageSource) {
+	if tintCache != nil {
+		tintCache.Call("delete", img.ImageSource())
+	}
+}
@@ -0,0 +1,54 @@
+package canvas
+
+// transformStack mirrors the transform and the save/restore stack of a context.
+type transformStack struct {
+	current Matrix
+	saved   []Matrix
+}
+
+func (t *transformStack) apply(m Matrix) {
+	t.current = t.current.Multiply(m)
+}
+
+func (t *transformStack) set(m Matrix) {
+	t.current = m
+}
+
+func (t *transformStack) save() {
+	t.saved = append(t.saved, t.current)
+}
+
+func (t *transformStack) restore() {
+	// like the context, ignore a restore without a matching save
+	if n := len(t.saved); n > 0 {
+		t.current = t.saved[n-1]
+		t.saved = t.saved[:n-1]
+	}
+}
+
+// TrackTransform turns on mirroring of the current transform in Go: Save, Restore,
+// Scale, Rotate, Translate, Transform and SetTransform called through ctx update a Go
+// copy, which CurrentTransform then returns without calling into the browser.
+// Tracking starts from the transform reported by GetMatrix.
+//
+// Only calls made through this *Context2D are seen. Changes made by JavaScript code,
+// through another wrapper of the same context, or by resizing the canvas other than with
+// SetSize or WithAutoResize (which resets the context) go unnoticed; call
+// TrackTransform again to resynchronize.
+func (ctx *Context2D) TrackTransform() {
+	ctx.xf = &transformStack{current: ctx.GetMatrix()}
+}
+
+// StopTrackingTransform turns off the mirroring enabled by TrackTransform.
+func (ctx *Context2D) StopTrackingTransform() {
+	ctx.xf = nil
+}
+
+// CurrentTransform returns the current transform: the tracked copy if TrackTransform
+// is on, otherwise the result of GetMatrix.
+func (ctx *Context2D) CurrentTransform() Matrix {
+	if ctx.xf != nil {
+		return ctx.xf.current
+	}
+	return ctx.GetMatrix()
+}
@@ -0,0 +1,263 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+type boxHandle int
+
+const (
+	handleNone boxHandle = iota
+	handleMove
+	handleRotate
+	handleScale
+)
+
+// TransformBox is an editing widget that draws move, scale and rotate handles around
+// a box and lets the user drag them. The box is drawn on an overlay context; the
+// resulting transform, which maps the original Box onto its edited position, is
+// reported through OnChange and Matrix.
+//
+// Dragging inside the box moves it, dragging a corner or edge handle scales it with the
+// opposite side held in place and dragging the handle above the top edge rotates it
+// around its centre.
+type TransformBox struct {
+	// Box is the untransformed bounds of the edited content.
+	Box Rect
+	// X and Y translate the box, ScaleX and ScaleY scale it and Rotation rotates it
+	// (in radians), all about the centre of Box.
+	X, Y, ScaleX, ScaleY, Rotation float64
+	// KeepAspect makes corner handles scale uniformly.
+	KeepAspect bool
+	// HandleSize is the side of a handle square in pixels, 8 by default.
+	HandleSize float64
+	// Color of the frame and handles, "#1e90ff" by default.
+	Color string
+	// OnChange is called with the new transform after every drag step.
+	OnChange func(m Matrix)
+	// Underlay is called before each redraw to repaint what lies below the box.
+	// If nil the whole overlay is cleared.
+	Underlay func(ctx *Context2D)
+
+	ctx    *Context2D
+	canvas *Canvas
+	remove func()
+
+	drag       boxHandle
+	hx, hy     float64
+	start      Point
+	startX     float64
+	startY     float64
+	startAngle float64
+	anchor     Point
+}
+
+// NewTransformBox creates a TransformBox around box, listening to pointer events on c
+// and drawing on overlay. Call Detach to stop listening.
+func NewTransformBox(c *Canvas, overlay *Context2D, box Rect) *TransformBox {
+	t := &TransformBox{
+		Box:        box,
+		ScaleX:     1,
+		ScaleY:     1,
+		HandleSize: 8,
+		Color:      "#1e90ff",
+		ctx:        overlay,
+		canvas:     c,
+	}
+	t.remove = c.onPointerDrag(t.down, t.move, t.up)
+	t.Draw()
+	return t
+}
+
+// Detach removes the event listeners.
+func (t *TransformBox) Detach() {
+	t.remove()
+	t.canvas.Get("style").Set("cursor", "")
+}
+
+// Reset clears the transform.
+func (t *TransformBox) Reset() {
+	t.X, t.Y, t.ScaleX, t.ScaleY, t.Rotation = 0, 0, 1, 1, 0
+	t.Draw()
+}
+
+// Matrix returns the transform mapping Box onto its edited position.
+func (t *TransformBox) Matrix() Matrix {
+	cx, cy := t.Box.X+t.Box.W/2, t.Box.Y+t.Box.H/2
+	sin, cos := math.Sincos(t.Rotation)
+	a, b := cos*t.ScaleX, sin*t.ScaleX
+	c, d := -sin*t.ScaleY, cos*t.ScaleY
+	return Matrix{a, b, c, d,
+		cx + t.X - a*cx - c*cy,
+		cy + t.Y - b*cx - d*cy,
+	}
+}
+
+// Apply multiplies the transform of ctx by the box transform, so that content drawn
+// in the original Box coordinates appears where the box has been moved to.
+func (t *TransformBox) Apply(ctx *Context2D) {
+	ctx.TransformBy(t.Matrix())
+}
+
+// Draw repaints the overlay with the frame and handles.
+func (t *TransformBox) Draw() {
+	ctx := t.ctx
+	if t.Underlay != nil {
+		t.Underlay(ctx)
+	} else {
+		ctx.Clear()
+	}
+	corners := t.corners()
+	ctx.Save()
+	ctx.StrokeStyle = t.Color
+	ctx.LineWidth = 1
+	ctx.BeginPath()
+	for i, p := range corners {
+		if i == 0 {
+			ctx.MoveTo(p.X, p.Y)
+		} else {
+			ctx.LineTo(p.X, p.Y)
+		}
+	}
+	ctx.ClosePath()
+	top := t.local(0, -1)
+	rot := t.rotateHandle()
+	ctx.MoveTo(top.X, top.Y)
+	ctx.LineTo(rot.X, rot.Y)
+	ctx.Stroke()
+
+	s := t.HandleSize
+	ctx.FillStyle = "#fff"
+	for _, h := range t.scaleHandles() {
+		p := t.local(h.X, h.Y)
+		ctx.FillRect(p.X-s/2, p.Y-s/2, s, s)
+		ctx.StrokeRect(p.X-s/2, p.Y-s/2, s, s)
+	}
+	ctx.BeginPath()
+	ctx.Arc(rot.X, rot.Y, s/2, 0, 2*math.Pi)
+	ctx.Fill()
+	ctx.Stroke()
+	ctx.Restore()
+}
+
+// local maps a point given in box units, (-1, -1) being the top left corner and
+// (1, 1) the bottom right one, to canvas coordinates.
+func (t *TransformBox) local(u, v float64) Point {
+	return t.Matrix().TransformPoint(Point{
+		X: t.Box.X + t.Box.W*(u+1)/2,
+		Y: t.Box.Y + t.Box.H*(v+1)/2,
+	})
+}
+
+func (t *TransformBox) corners() []Point {
+	return []Point{t.local(-1, -1), t.local(1, -1), t.local(1, 1), t.local(-1, 1)}
+}
+
+func (t *TransformBox) scaleHandles() []Point {
+	return []Point{{-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}}
+}
+
+func (t *TransformBox) rotateHandle() Point {
+	top := t.local(0, -1)
+	// keep the handle outside the box even when it is flipped vertically
+	up := Point{X: math.Sin(t.Rotation), Y: -math.Cos(t.Rotation)}
+	if t.ScaleY < 0 {
+		up = up.Mul(-1)
+	}
+	return top.Add(up.Mul(4 * t.HandleSize))
+}
+
+func (t *TransformBox) hit(p Point) (h boxHandle, hx, hy float64) {
+	near := func(q Point) bool {
+		return math.Abs(p.X-q.X) <= t.HandleSize && math.Abs(p.Y-q.Y) <= t.HandleSize
+	}
+	if near(t.rotateHandle()) {
+		return handleRotate, 0, 0
+	}
+	for _, s := range t.scaleHandles() {
+		if near(t.local(s.X, s.Y)) {
+			return handleScale, s.X, s.Y
+		}
+	}
+	if PointInPolygon(p, t.corners()) {
+		return handleMove, 0, 0
+	}
+	return handleNone, 0, 0
+}
+
+func (t *TransformBox) center() Point {
+	return Point{X: t.Box.X + t.Box.W/2 + t.X, Y: t.Box.Y + t.Box.H/2 + t.Y}
+}
+
+func (t *TransformBox) down(p Point, ev *js.Object) {
+	h, hx, hy := t.hit(p)
+	if h == handleNone {
+		return
+	}
+	t.canvas.Call("setPointerCapture", ev.Get("pointerId"))
+	t.drag, t.hx, t.hy = h, hx, hy
+	t.start = p
+	t.startX, t.startY = t.X, t.Y
+	c := t.center()
+	t.startAngle = t.Rotation - math.Atan2(p.Y-c.Y, p.X-c.X)
+	t.anchor = t.local(-hx, -hy)
+}
+
+func (t *TransformBox) move(p Point, ev *js.Object) {
+	switch t.drag {
+	case handleNone:
+		cursor := ""
+		switch h, _, _ := t.hit(p); h {
+		case handleMove:
+			cursor = "move"
+		case handleRotate:
+			cursor = "grab"
+		case handleScale:
+			cursor = "crosshair"
+		}
+		t.canvas.Get("style").Set("cursor", cursor)
+		return
+	case handleMove:
+		t.X = t.startX + p.X - t.start.X
+		t.Y = t.startY + p.Y - t.start.Y
+	case handleRotate:
+		c := t.center()
+		t.Rotation = t.startAngle + math.Atan2(p.Y-c.Y, p.X-c.X)
+	case handleScale:
+		t.scaleTo(p)
+	}
+	t.Draw()
+	if t.OnChange != nil {
+		t.OnChange(t.Matrix())
+	}
+}
+
+// scaleTo scales the box so that the dragged handle follows p while the anchor on the
+// opposite side stays put.
+func (t *TransformBox) scaleTo(p Point) {
+	sin, cos := math.Sincos(t.Rotation)
+	dx, dy := p.X-t.anchor.X, p.Y-t.anchor.Y
+	// p relative to the anchor in the unrotated frame of the box
+	qx, qy := dx*cos+dy*sin, -dx*sin+dy*cos
+	if t.hx != 0 && t.Box.W != 0 {
+		t.ScaleX = qx / (t.hx * t.Box.W)
+	}
+	if t.hy != 0 && t.Box.H != 0 {
+		t.ScaleY = qy / (t.hy * t.Box.H)
+	}
+	if t.KeepAspect && t.hx != 0 && t.hy != 0 {
+		s := math.Max(math.Abs(t.ScaleX), math.Abs(t.ScaleY))
+		t.ScaleX = math.Copysign(s, t.ScaleX)
+		t.ScaleY = math.Copysign(s, t.ScaleY)
+	}
+	// offset from the anchor to the new centre, rotated back into canvas space
+	ox, oy := t.hx*t.Box.W*t.ScaleX/2, t.hy*t.Box.H*t.ScaleY/2
+	t.X = t.anchor.X + ox*cos - oy*sin - (t.Box.X + t.Box.W/2)
+	t.Y = t.anchor.Y + ox*sin + oy*cos - (t.Box.Y + t.Box.H/2)
+}
+
+func (t *TransformBox) up(p Point, ev *js.Object) {
+	t.drag = handleNone
+}
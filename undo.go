@@ -0,0 +1,258 @@
+package canvas
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// UndoAction is an undoable operation for UndoStack.
+type UndoAction interface {
+	// Undo reverts the operation.
+	Undo()
+	// Redo performs it again after Undo.
+	Redo()
+}
+
+// UndoSizer may be implemented by an UndoAction to report the memory it holds,
+// in bytes, for UndoStack.MaxBytes.
+type UndoSizer interface {
+	Size() int
+}
+
+// UndoCoalescer may be implemented by an UndoAction to absorb the action pushed
+// right after it under the same key, e.g. to fold a run of small edits into one step.
+// Coalesce returns false if next cannot be merged.
+type UndoCoalescer interface {
+	Coalesce(next UndoAction) bool
+}
+
+type undoFunc struct {
+	undo, redo func()
+}
+
+func (f undoFunc) Undo() { f.undo() }
+func (f undoFunc) Redo() { f.redo() }
+
+// UndoFunc returns an UndoAction calling undo and redo.
+func UndoFunc(undo, redo func()) UndoAction {
+	return undoFunc{undo, redo}
+}
+
+type undoEntry struct {
+	action UndoAction
+	key    string
+	time   float64
+	size   int
+}
+
+// UndoStack keeps a history of undoable actions. Actions are either commands
+// implementing UndoAction, or pixel snapshots of a canvas taken with BeginSnapshot
+// and EndSnapshot. Actions pushed with the same non-empty key within CoalesceWindow
+// of each other are merged into one step.
+type UndoStack struct {
+	// MaxSteps limits the number of undo steps kept; 0 means no limit.
+	MaxSteps int
+	// MaxBytes limits the memory held by the undo steps, as reported by UndoSizer;
+	// 0 means no limit. The most recent step is always kept.
+	MaxBytes int
+	// CoalesceWindow is the time in milliseconds within which actions with the same
+	// key are merged; 0 means 500 and a negative value disables merging.
+	CoalesceWindow float64
+	// OnChange is called after every change of the history.
+	OnChange func()
+
+	undo, redo []undoEntry
+	bytes      int
+	pending    *snapshotAction
+}
+
+// NewUndoStack creates an empty UndoStack keeping at most maxSteps steps.
+func NewUndoStack(maxSteps int) *UndoStack {
+	return &UndoStack{MaxSteps: maxSteps}
+}
+
+// Do performs a by calling its Redo method and pushes it.
+func (s *UndoStack) Do(key string, a UndoAction) {
+	a.Redo()
+	s.Push(key, a)
+}
+
+// Push records a, which has already been performed. Pushing clears the redo history.
+func (s *UndoStack) Push(key string, a UndoAction) {
+	now := js.Global.Get("performance").Call("now").Float()
+	window := s.CoalesceWindow
+	if window == 0 {
+		window = 500
+	}
+	s.dropRedo()
+	if n := len(s.undo); n > 0 && key != "" {
+		top := &s.undo[n-1]
+		if c, ok := top.action.(UndoCoalescer); ok && top.key == key &&
+			now-top.time <= window && c.Coalesce(a) {
+			s.bytes -= top.size
+			top.size = sizeOf(top.action)
+			s.bytes += top.size
+			top.time = now
+			s.changed()
+			return
+		}
+	}
+	e := undoEntry{action: a, key: key, time: now, size: sizeOf(a)}
+	s.undo = append(s.undo, e)
+	s.bytes += e.size
+	s.trim()
+	s.changed()
+}
+
+// Undo reverts the last step, reporting false if there is none.
+func (s *UndoStack) Undo() bool {
+	n := len(s.undo)
+	if n == 0 {
+		return false
+	}
+	e := s.undo[n-1]
+	s.undo = s.undo[:n-1]
+	e.action.Undo()
+	s.redo = append(s.redo, e)
+	s.changed()
+	return true
+}
+
+// Redo performs the last undone step again, reporting false if there is none.
+func (s *UndoStack) Redo() bool {
+	n := len(s.redo)
+	if n == 0 {
+		return false
+	}
+	e := s.redo[n-1]
+	s.redo = s.redo[:n-1]
+	e.action.Redo()
+	// a redone step must not swallow the next action
+	e.key = ""
+	s.undo = append(s.undo, e)
+	s.changed()
+	return true
+}
+
+// CanUndo reports whether Undo has a step to apply.
+func (s *UndoStack) CanUndo() bool { return len(s.undo) > 0 }
+
+// CanRedo reports whether Redo has a step to apply.
+func (s *UndoStack) CanRedo() bool { return len(s.redo) > 0 }
+
+// Bytes returns the memory held by all steps as reported by UndoSizer.
+func (s *UndoStack) Bytes() int {
+	return s.bytes
+}
+
+// Clear drops the whole history.
+func (s *UndoStack) Clear() {
+	s.undo, s.redo = nil, nil
+	s.bytes = 0
+	s.pending = nil
+	s.changed()
+}
+
+// BeginSnapshot copies the area of ctx's canvas, in canvas pixels, that is about to
+// be edited. An empty area means the whole canvas. Call EndSnapshot when the edit
+// is done to push it as one undo step.
+func (s *UndoStack) BeginSnapshot(ctx *Context2D, area Rect) {
+	c := ctx.Canvas()
+	if area.W <= 0 || area.H <= 0 {
+		w, h := c.Size()
+		area = Rect{W: float64(w), H: float64(h)}
+	}
+	area = Rect{
+		X: math.Floor(area.X), Y: math.Floor(area.Y),
+		W: math.Ceil(area.X+area.W) - math.Floor(area.X),
+		H: math.Ceil(area.Y+area.H) - math.Floor(area.Y),
+	}
+	s.pending = &snapshotAction{ctx: ctx, area: area, before: copyArea(c, area)}
+}
+
+// EndSnapshot pushes the edit started by BeginSnapshot, capturing the area again as
+// the redo state. Consecutive snapshots of the same area with the same key within
+// CoalesceWindow become one step.
+func (s *UndoStack) EndSnapshot(key string) {
+	a := s.pending
+	if a == nil {
+		return
+	}
+	s.pending = nil
+	a.after = copyArea(a.ctx.Canvas(), a.area)
+	s.Push(key, a)
+}
+
+func (s *UndoStack) dropRedo() {
+	for _, e := range s.redo {
+		s.bytes -= e.size
+	}
+	s.redo = nil
+}
+
+func (s *UndoStack) trim() {
+	for len(s.undo) > 1 && (s.MaxSteps > 0 && len(s.undo) > s.MaxSteps || s.MaxBytes > 0 && s.bytes > s.MaxBytes) {
+		s.bytes -= s.undo[0].size
+		s.undo[0] = undoEntry{}
+		s.undo = s.undo[1:]
+	}
+}
+
+func (s *UndoStack) changed() {
+	if s.OnChange != nil {
+		s.OnChange()
+	}
+}
+
+func sizeOf(a UndoAction) int {
+	if sz, ok := a.(UndoSizer); ok {
+		return sz.Size()
+	}
+	return 0
+}
+
+// snapshotAction restores an area of a canvas from offscreen copies taken before
+// and after an edit.
+type snapshotAction struct {
+	ctx           *Context2D
+	area          Rect
+	before, after *Canvas
+}
+
+func (a *snapshotAction) Undo() { a.restore(a.before) }
+func (a *snapshotAction) Redo() { a.restore(a.after) }
+
+func (a *snapshotAction) restore(src *Canvas) {
+	ctx := a.ctx
+	ctx.Save()
+	ctx.SetTransform(1, 0, 0, 1, 0, 0)
+	ctx.GlobalAlpha = 1
+	ctx.SetCompositeOp(CompositeCopy)
+	ctx.BeginPath()
+	ctx.Rect(a.area.X, a.area.Y, a.area.W, a.area.H)
+	ctx.Clip()
+	ctx.DrawImage(src, a.area.X, a.area.Y, a.area.W, a.area.H)
+	ctx.Restore()
+}
+
+func (a *snapshotAction) Size() int {
+	// two RGBA copies of the area
+	return 2 * 4 * int(a.area.W*a.area.H)
+}
+
+func (a *snapshotAction) Coalesce(next UndoAction) bool {
+	n, ok := next.(*snapshotAction)
+	if !ok || n.ctx.Object != a.ctx.Object || n.area != a.area {
+		return false
+	}
+	a.after = n.after
+	return true
+}
+
+// copyArea returns a new canvas holding the given pixel area of c.
+func copyArea(c *Canvas, area Rect) *Canvas {
+	dst := Create(int(area.W), int(area.H))
+	dst.GetContext2D().DrawImageRegion(c, area.X, area.Y, area.W, area.H, 0, 0, area.W, area.H)
+	return dst
+}
@@ -0,0 +1,171 @@
+package canvas
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ErrCameraUnavailable is returned when the browser has no getUserMedia support,
+// for example on pages not served over https.
+var ErrCameraUnavailable = errors.New("canvas: camera capture not supported")
+
+// CameraConstraints selects the camera passed to getUserMedia. Zero values leave
+// the choice to the browser.
+type CameraConstraints struct {
+	// Width and Height are the ideal resolution in pixels.
+	Width, Height int
+	// FacingMode is "user" for the front camera or "environment" for the back camera.
+	FacingMode string
+	// DeviceID picks a specific camera from navigator.mediaDevices.enumerateDevices.
+	DeviceID string
+}
+
+func (c CameraConstraints) video() interface{} {
+	v := js.M{}
+	if c.Width > 0 {
+		v["width"] = js.M{"ideal": c.Width}
+	}
+	if c.Height > 0 {
+		v["height"] = js.M{"ideal": c.Height}
+	}
+	if c.FacingMode != "" {
+		v["facingMode"] = c.FacingMode
+	}
+	if c.DeviceID != "" {
+		v["deviceId"] = js.M{"exact": c.DeviceID}
+	}
+	if len(v) == 0 {
+		return true
+	}
+	return v
+}
+
+// VideoSource is a playing <video> element fed by a camera, usable as a CanvasImageSource.
+type VideoSource struct {
+	// Video is the HTMLVideoElement, which is not attached to the document.
+	Video  *js.Object
+	stream *js.Object
+	ticker *Ticker
+}
+
+// CaptureCamera asks for access to a camera and starts playing it into an offscreen
+// video element. It blocks until the user granted or denied access and the first frame
+// is available, so it must be called from a goroutine, not from an event handler.
+func CaptureCamera(constraints CameraConstraints) (*VideoSource, error) {
+	devices := js.Global.Get("navigator").Get("mediaDevices")
+	if devices == js.Undefined || devices.Get("getUserMedia") == js.Undefined {
+		return nil, ErrCameraUnavailable
+	}
+	type result struct {
+		stream *js.Object
+		err    error
+	}
+	ch := make(chan result, 1)
+	devices.Call("getUserMedia", js.M{"video": constraints.video(), "audio": false}).Call("then",
+		func(stream *js.Object) { ch <- result{stream: stream} },
+		func(e *js.Object) { ch <- result{err: errors.New("canvas: camera access failed: " + e.String())} },
+	)
+	r := <-ch
+	if r.err != nil {
+		return nil, r.err
+	}
+	video := js.Global.Get("document").Call("createElement", "video")
+	video.Set("muted", true)
+	video.Set("playsInline", true)
+	video.Set("srcObject", r.stream)
+	v := &VideoSource{Video: video, stream: r.stream}
+	// both callbacks may fire and must not block, only the first result counts
+	ready := make(chan error, 1)
+	signal := func(err error) {
+		select {
+		case ready <- err:
+		default:
+		}
+	}
+	video.Set("onloadeddata", func() {
+		video.Set("onloadeddata", nil)
+		signal(nil)
+	})
+	video.Call("play").Call("catch", func(e *js.Object) {
+		signal(errors.New("canvas: playing camera stream failed: " + e.String()))
+	})
+	if err := <-ready; err != nil {
+		v.Stop()
+		return nil, err
+	}
+	return v, nil
+}
+
+// ImageSource returns the video element.
+func (v *VideoSource) ImageSource() *js.Object {
+	return v.Video
+}
+
+// Size returns the resolution the camera delivers.
+func (v *VideoSource) Size() (width, height float64) {
+	return SourceSize(v)
+}
+
+// Draw paints the current frame into ctx so that it covers the w×h area at x, y,
+// cropping the frame to keep its aspect ratio. With mirror the frame is flipped
+// horizontally, as users expect from a front camera.
+func (v *VideoSource) Draw(ctx *Context2D, x, y, w, h float64, mirror bool) {
+	vw, vh := v.Size()
+	if vw == 0 || vh == 0 {
+		return
+	}
+	sw, sh := vw, vh
+	if vw*h > vh*w {
+		sw = vh * w / h
+	} else {
+		sh = vw * h / w
+	}
+	sx, sy := (vw-sw)/2, (vh-sh)/2
+	if !mirror {
+		ctx.DrawImageRegion(v, sx, sy, sw, sh, x, y, w, h)
+		return
+	}
+	ctx.Save()
+	ctx.Translate(x+w, y)
+	ctx.Scale(-1, 1)
+	ctx.DrawImageRegion(v, sx, sy, sw, sh, 0, 0, w, h)
+	ctx.Restore()
+}
+
+// Start paints every animation frame of the camera over the whole canvas of ctx and
+// then calls overlay, if not nil, to draw on top of it, e.g. for filters or stickers.
+// Calling Start again replaces the previous loop.
+func (v *VideoSource) Start(ctx *Context2D, mirror bool, overlay func(ctx *Context2D, f FrameInfo)) {
+	v.StopDrawing()
+	c := ctx.Get("canvas")
+	v.ticker = NewTicker(func(f FrameInfo) {
+		ctx.Save()
+		ctx.SetTransform(1, 0, 0, 1, 0, 0)
+		v.Draw(ctx, 0, 0, c.Get("width").Float(), c.Get("height").Float(), mirror)
+		ctx.Restore()
+		if overlay != nil {
+			overlay(ctx, f)
+		}
+	})
+	v.ticker.Start()
+}
+
+// StopDrawing ends the loop started by Start; the camera keeps running.
+func (v *VideoSource) StopDrawing() {
+	if v.ticker != nil {
+		v.ticker.Stop()
+		v.ticker = nil
+	}
+}
+
+// Stop ends drawing and releases the camera.
+func (v *VideoSource) Stop() {
+	v.StopDrawing()
+	v.Video.Call("pause")
+	v.Video.Set("srcObject", nil)
+	tracks := v.stream.Call("getTracks")
+	for i := 0; i < tracks.Length(); i++ {
+		tracks.Index(i).Call("stop")
+	}
+}